@@ -0,0 +1,148 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/LordVillain/BSUComputerGraphicsLabs/lab1/colors"
+)
+
+// ---------- Photoshop Color Swatches (.aco) ----------
+//
+// Файл состоит из одной или двух секций (все числа big-endian):
+//
+//	версия (uint16: 1 - без имен, 2 - с именами), число цветов (uint16)
+//	цвет: пространство (uint16) и четыре значения uint16;
+//	      в версии 2 далее имя: длина в символах с завершающим нулем (uint32), строка UTF-16
+//
+// Обычно Photoshop пишет секцию версии 1, а за ней ту же палитру в версии 2.
+// Пространства и значения:
+//
+//	0 - RGB:  0..65535 на канал
+//	1 - HSB:  тон 0..65535 (= 0..360°), насыщенность и яркость 0..65535
+//	2 - CMYK: 0..65535, где 0 - 100% краски
+//	7 - Lab (D50): L 0..10000, a и b - со знаком, -12800..12700
+//	8 - Grayscale: 0..10000 - доля черного
+
+const (
+	acoRGB  = 0
+	acoHSB  = 1
+	acoCMYK = 2
+	acoLab  = 7
+	acoGray = 8
+)
+
+func decodeACO(data []byte) (SwatchFile, error) {
+	var file SwatchFile
+	r := bytes.NewReader(data)
+
+	for r.Len() > 0 {
+		var header struct{ Version, Count uint16 }
+		if err := binary.Read(r, binary.BigEndian, &header); err != nil {
+			return file, err
+		}
+		if header.Version != 1 && header.Version != 2 {
+			return file, fmt.Errorf("unsupported version %d", header.Version)
+		}
+
+		section := make([]SwatchColor, header.Count)
+		for i := range section {
+			var entry struct {
+				Space  uint16
+				Values [4]uint16
+			}
+			if err := binary.Read(r, binary.BigEndian, &entry); err != nil {
+				return file, fmt.Errorf("color %d: %v", i, err)
+			}
+			color, err := acoColor(entry.Space, entry.Values)
+			if err != nil {
+				return file, fmt.Errorf("color %d: %v", i, err)
+			}
+			section[i].Color = color
+			if header.Version == 2 {
+				if section[i].Name, err = readUTF16(r, 4); err != nil {
+					return file, fmt.Errorf("color %d: %v", i, err)
+				}
+			}
+		}
+		// секция версии 2 повторяет цвета версии 1 и добавляет имена - она заменяет предыдущую
+		file.Colors = section
+	}
+	if file.Colors == nil {
+		return file, errors.New("empty file")
+	}
+	return file, nil
+}
+
+// acoColor переводит значения ACO в запрос конвертации
+func acoColor(space uint16, v [4]uint16) (ConvertRequest, error) {
+	switch space {
+	case acoRGB:
+		return rgbRequest(float64(v[0])/257, float64(v[1])/257, float64(v[2])/257), nil
+	case acoHSB:
+		return ConvertRequest{Model: "hsv", Values: map[string]float64{
+			"h": float64(v[0]) / 65535 * 360, "s": float64(v[1]) / 65535 * 100, "v": float64(v[2]) / 65535 * 100,
+		}}, nil
+	case acoCMYK:
+		ink := func(x uint16) float64 { return 100 - float64(x)/65535*100 }
+		return ConvertRequest{Model: "cmyk", Values: map[string]float64{
+			"c": ink(v[0]), "m": ink(v[1]), "y": ink(v[2]), "k": ink(v[3]),
+		}}, nil
+	case acoLab:
+		return cssLabRequest(colors.Lab{
+			L: float64(v[0]) / 100,
+			A: float64(int16(v[1])) / 100,
+			B: float64(int16(v[2])) / 100,
+		}), nil
+	case acoGray:
+		return ConvertRequest{Model: "cmyk", Values: map[string]float64{"c": 0, "m": 0, "y": 0, "k": float64(v[0]) / 100}}, nil
+	}
+	return ConvertRequest{}, fmt.Errorf("unsupported color space %d", space)
+}
+
+func encodeACO(name string, entries []swatchEntry, model string) ([]byte, error) {
+	if len(entries) > math.MaxUint16 {
+		return nil, fmt.Errorf("aco holds at most %d colors", math.MaxUint16)
+	}
+
+	type acoEntry struct {
+		Space  uint16
+		Values [4]uint16
+	}
+	values := make([]acoEntry, len(entries))
+	for i, e := range entries {
+		switch model {
+		case "cmyk":
+			c := e.resp.CMYK
+			ink := func(x float64) uint16 { return uint16(math.Round((100 - x) / 100 * 65535)) }
+			values[i] = acoEntry{acoCMYK, [4]uint16{ink(c.C), ink(c.M), ink(c.Y), ink(c.K)}}
+		case "lab":
+			lab := swatchLab(e.resp)
+			values[i] = acoEntry{acoLab, [4]uint16{
+				uint16(math.Round(clampFloat(lab.L, 0, 100) * 100)),
+				uint16(int16(math.Round(clampFloat(lab.A, -128, 127) * 100))),
+				uint16(int16(math.Round(clampFloat(lab.B, -128, 127) * 100))),
+			}}
+		default:
+			rgb := e.resp.RGB
+			values[i] = acoEntry{acoRGB, [4]uint16{uint16(rgb.R * 257), uint16(rgb.G * 257), uint16(rgb.B * 257)}}
+		}
+	}
+
+	var out bytes.Buffer
+	for _, version := range []uint16{1, 2} {
+		binary.Write(&out, binary.BigEndian, [2]uint16{version, uint16(len(entries))})
+		for i, v := range values {
+			binary.Write(&out, binary.BigEndian, v)
+			if version == 2 {
+				if err := writeUTF16(&out, entries[i].name, 4); err != nil {
+					return nil, fmt.Errorf("colors[%d]: %v", i, err)
+				}
+			}
+		}
+	}
+	return out.Bytes(), nil
+}
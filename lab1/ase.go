@@ -0,0 +1,227 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"unicode/utf16"
+
+	"github.com/LordVillain/BSUComputerGraphicsLabs/lab1/colors"
+)
+
+// ---------- Adobe Swatch Exchange (.ase) ----------
+//
+// Формат (все числа big-endian):
+//
+//	"ASEF", версия 1.0 (2 x uint16), число блоков (uint32)
+//	блок: тип (uint16), длина данных (uint32), данные
+//	  0xC001 - начало группы: имя
+//	  0xC002 - конец группы
+//	  0x0001 - цвет: имя, модель ("RGB ", "CMYK", "LAB ", "Gray"), значения float32, тип (uint16)
+//	имя: длина в символах UTF-16 вместе с завершающим нулем (uint16), строка UTF-16
+//
+// RGB, CMYK и Gray задаются долями 0..1, L в LAB - тоже долей (0..1 = 0..100), a и b - как есть.
+// Lab в палитрах Adobe отсчитывается от белого D50.
+
+const (
+	aseGroupStart = 0xC001
+	aseGroupEnd   = 0xC002
+	aseColorEntry = 0x0001
+)
+
+func decodeASE(data []byte) (SwatchFile, error) {
+	var file SwatchFile
+	r := bytes.NewReader(data)
+
+	var header struct {
+		Signature    [4]byte
+		Major, Minor uint16
+		Blocks       uint32
+	}
+	if err := binary.Read(r, binary.BigEndian, &header); err != nil || string(header.Signature[:]) != "ASEF" {
+		return file, errors.New("not an ASE file")
+	}
+	if header.Major != 1 {
+		return file, fmt.Errorf("unsupported version %d.%d", header.Major, header.Minor)
+	}
+
+	group := ""
+	for i := 0; i < int(header.Blocks); i++ {
+		var block struct {
+			Type   uint16
+			Length uint32
+		}
+		if err := binary.Read(r, binary.BigEndian, &block); err != nil {
+			return file, fmt.Errorf("block %d: %v", i, err)
+		}
+		if int64(block.Length) > int64(r.Len()) {
+			return file, fmt.Errorf("block %d: truncated", i)
+		}
+		buf := make([]byte, block.Length)
+		io.ReadFull(r, buf)
+		body := bytes.NewReader(buf)
+
+		switch block.Type {
+		case aseGroupStart:
+			name, err := readUTF16(body, 2)
+			if err != nil {
+				return file, fmt.Errorf("block %d: %v", i, err)
+			}
+			group = name
+		case aseGroupEnd:
+			group = ""
+		case aseColorEntry:
+			c, err := decodeASEColor(body)
+			if err != nil {
+				return file, fmt.Errorf("block %d: %v", i, err)
+			}
+			c.Group = group
+			file.Colors = append(file.Colors, c)
+		}
+		// блоки других типов пропускаем
+	}
+	return file, nil
+}
+
+func decodeASEColor(r io.Reader) (SwatchColor, error) {
+	name, err := readUTF16(r, 2)
+	if err != nil {
+		return SwatchColor{}, err
+	}
+	var model [4]byte
+	if _, err := io.ReadFull(r, model[:]); err != nil {
+		return SwatchColor{}, err
+	}
+
+	n := map[string]int{"RGB ": 3, "CMYK": 4, "LAB ": 3, "Gray": 1}[string(model[:])]
+	if n == 0 {
+		return SwatchColor{}, fmt.Errorf("unsupported color model %q", model[:])
+	}
+	v := make([]float32, n)
+	if err := binary.Read(r, binary.BigEndian, v); err != nil {
+		return SwatchColor{}, err
+	}
+
+	c := SwatchColor{Name: name}
+	switch string(model[:]) {
+	case "RGB ":
+		c.Color = rgbRequest(float64(v[0])*255, float64(v[1])*255, float64(v[2])*255)
+	case "CMYK":
+		c.Color = ConvertRequest{Model: "cmyk", Values: map[string]float64{
+			"c": float64(v[0]) * 100, "m": float64(v[1]) * 100, "y": float64(v[2]) * 100, "k": float64(v[3]) * 100,
+		}}
+	case "LAB ":
+		c.Color = cssLabRequest(colors.Lab{L: float64(v[0]) * 100, A: float64(v[1]), B: float64(v[2])})
+	case "Gray":
+		c.Color = rgbRequest(float64(v[0])*255, float64(v[0])*255, float64(v[0])*255)
+	}
+	return c, nil
+}
+
+func encodeASE(name string, entries []swatchEntry, model string) ([]byte, error) {
+	var blocks bytes.Buffer
+	count := 0
+	writeBlock := func(typ uint16, body []byte) {
+		binary.Write(&blocks, binary.BigEndian, typ)
+		binary.Write(&blocks, binary.BigEndian, uint32(len(body)))
+		blocks.Write(body)
+		count++
+	}
+
+	group := ""
+	for i, e := range entries {
+		if e.group != group {
+			if group != "" {
+				writeBlock(aseGroupEnd, nil)
+			}
+			if e.group != "" {
+				var body bytes.Buffer
+				if err := writeUTF16(&body, e.group, 2); err != nil {
+					return nil, fmt.Errorf("colors[%d]: group: %v", i, err)
+				}
+				writeBlock(aseGroupStart, body.Bytes())
+			}
+			group = e.group
+		}
+
+		var body bytes.Buffer
+		if err := writeUTF16(&body, e.name, 2); err != nil {
+			return nil, fmt.Errorf("colors[%d]: %v", i, err)
+		}
+		var values []float32
+		switch model {
+		case "cmyk":
+			c := e.resp.CMYK
+			body.WriteString("CMYK")
+			values = []float32{float32(c.C / 100), float32(c.M / 100), float32(c.Y / 100), float32(c.K / 100)}
+		case "lab":
+			lab := swatchLab(e.resp)
+			body.WriteString("LAB ")
+			values = []float32{float32(lab.L / 100), float32(lab.A), float32(lab.B)}
+		default:
+			rgb := e.resp.RGB
+			body.WriteString("RGB ")
+			values = []float32{float32(rgb.R) / 255, float32(rgb.G) / 255, float32(rgb.B) / 255}
+		}
+		binary.Write(&body, binary.BigEndian, values)
+		binary.Write(&body, binary.BigEndian, uint16(2)) // обычный (не глобальный и не плашечный) цвет
+		writeBlock(aseColorEntry, body.Bytes())
+	}
+	if group != "" {
+		writeBlock(aseGroupEnd, nil)
+	}
+
+	var out bytes.Buffer
+	out.WriteString("ASEF")
+	binary.Write(&out, binary.BigEndian, [2]uint16{1, 0})
+	binary.Write(&out, binary.BigEndian, uint32(count))
+	out.Write(blocks.Bytes())
+	return out.Bytes(), nil
+}
+
+// ---------- Строки UTF-16 (ASE, ACO) ----------
+
+// readUTF16 читает строку: длина в символах с завершающим нулем (lenSize байт), затем UTF-16BE
+func readUTF16(r io.Reader, lenSize int) (string, error) {
+	var n uint32
+	if lenSize == 2 {
+		var n16 uint16
+		if err := binary.Read(r, binary.BigEndian, &n16); err != nil {
+			return "", err
+		}
+		n = uint32(n16)
+	} else if err := binary.Read(r, binary.BigEndian, &n); err != nil {
+		return "", err
+	}
+	if n > math.MaxUint16 {
+		return "", errors.New("name is too long")
+	}
+	units := make([]uint16, n)
+	if err := binary.Read(r, binary.BigEndian, units); err != nil {
+		return "", err
+	}
+	for len(units) > 0 && units[len(units)-1] == 0 {
+		units = units[:len(units)-1]
+	}
+	return string(utf16.Decode(units)), nil
+}
+
+// writeUTF16 записывает строку в формате readUTF16. Длина с нулем не может превышать
+// math.MaxUint16 (поле ASE - uint16, ACO - столько же читает readUTF16): такую строку
+// не обрезаем молча, а возвращаем ошибку
+func writeUTF16(w io.Writer, s string, lenSize int) error {
+	units := append(utf16.Encode([]rune(s)), 0)
+	if len(units) > math.MaxUint16 {
+		return fmt.Errorf("name is longer than %d UTF-16 code units", math.MaxUint16-1)
+	}
+	if lenSize == 2 {
+		binary.Write(w, binary.BigEndian, uint16(len(units)))
+	} else {
+		binary.Write(w, binary.BigEndian, uint32(len(units)))
+	}
+	binary.Write(w, binary.BigEndian, units)
+	return nil
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// BatchItem - одна строка ответа пакетной конвертации (NDJSON).
+// При успехе содержит поля ConvertResponse, при ошибке - только index и error.
+// Ошибка всего потока (обрыв или испорченный JSON) приходит последней строкой
+// batchStreamError - без index, так как она не относится к конкретному элементу.
+type BatchItem struct {
+	Index int `json:"index"`
+	*ConvertResponse
+	Error string `json:"error,omitempty"`
+}
+
+// batchStreamError - последняя строка ответа, если поток запросов не удалось дочитать
+type batchStreamError struct {
+	Error string `json:"error"`
+}
+
+// Сколько строк отдаем клиенту между принудительными сбросами буфера
+const batchFlushEvery = 100
+
+// Максимальная длина одной строки NDJSON
+const batchMaxLine = 1 << 20
+
+// batchHandler принимает JSON-массив или поток NDJSON из ConvertRequest
+// и построчно возвращает результаты в формате NDJSON.
+// Ошибка в отдельном элементе не прерывает обработку остальных.
+func batchHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body := bufio.NewReader(r.Body)
+	first, err := peekNonSpace(body)
+	if err == io.EOF {
+		http.Error(w, "empty body", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, "read error: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	flusher, _ := w.(http.Flusher)
+	enc := json.NewEncoder(w)
+	count := 0
+
+	emit := func(index int, raw []byte) {
+		item := BatchItem{Index: index}
+		var req ConvertRequest
+		if err := json.Unmarshal(raw, &req); err != nil {
+			item.Error = "invalid json: " + err.Error()
+		} else if resp, err := convertColor(req); err != nil {
+			item.Error = err.Error()
+		} else {
+			item.ConvertResponse = &resp
+		}
+		enc.Encode(item)
+
+		count++
+		if flusher != nil && count%batchFlushEvery == 0 {
+			flusher.Flush()
+		}
+	}
+
+	if first == '[' {
+		err = readJSONArray(body, emit)
+	} else {
+		err = readNDJSON(body, emit)
+	}
+	if err != nil {
+		// заголовок уже отправлен - сообщаем об ошибке последней строкой потока
+		enc.Encode(batchStreamError{Error: err.Error()})
+	}
+	if flusher != nil {
+		flusher.Flush()
+	}
+}
+
+// readJSONArray читает элементы массива по одному, не загружая весь массив в память
+func readJSONArray(body io.Reader, emit func(index int, raw []byte)) error {
+	dec := json.NewDecoder(body)
+	if _, err := dec.Token(); err != nil { // '['
+		return fmt.Errorf("invalid json: %v", err)
+	}
+	for i := 0; dec.More(); i++ {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return fmt.Errorf("invalid json: %v", err)
+		}
+		emit(i, raw)
+	}
+	if _, err := dec.Token(); err != nil { // ']'
+		return fmt.Errorf("invalid json: %v", err)
+	}
+	return nil
+}
+
+// readNDJSON читает по одному объекту на строку; пустые строки пропускаются
+func readNDJSON(body io.Reader, emit func(index int, raw []byte)) error {
+	sc := bufio.NewScanner(body)
+	sc.Buffer(make([]byte, 0, 64*1024), batchMaxLine)
+	for i := 0; sc.Scan(); {
+		line := bytes.TrimSpace(sc.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		emit(i, line)
+		i++
+	}
+	if err := sc.Err(); err != nil {
+		return fmt.Errorf("read error: %v", err)
+	}
+	return nil
+}
+
+// peekNonSpace возвращает первый непробельный байт, не извлекая его из потока
+func peekNonSpace(r *bufio.Reader) (byte, error) {
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		switch b {
+		case ' ', '\t', '\r', '\n':
+			continue
+		}
+		return b, r.UnreadByte()
+	}
+}
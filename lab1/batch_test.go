@@ -0,0 +1,42 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestBatchConvert(t *testing.T) {
+	rec := httptest.NewRecorder()
+	body := `[{"css":"red"}, {"model":"plasma"}, {"css":"blue"}`
+	batchHandler(rec, httptest.NewRequest(http.MethodPost, "/api/convert/batch", strings.NewReader(body)))
+
+	var lines []map[string]any
+	sc := bufio.NewScanner(rec.Body)
+	for sc.Scan() {
+		var line map[string]any
+		if err := json.Unmarshal(sc.Bytes(), &line); err != nil {
+			t.Fatal(err)
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) != 4 {
+		t.Fatalf("got %d lines, want 4: %v", len(lines), lines)
+	}
+	for i, line := range lines[:3] {
+		if line["index"] != float64(i) {
+			t.Errorf("line %d: index %v", i, line["index"])
+		}
+	}
+	if lines[0]["error"] != nil || lines[1]["error"] == nil || lines[2]["error"] != nil {
+		t.Errorf("item errors: %v", lines[:3])
+	}
+
+	// незакрытый массив - ошибка потока, а не элемента: без index
+	if _, ok := lines[3]["index"]; ok || lines[3]["error"] == nil {
+		t.Errorf("stream error line = %v", lines[3])
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/LordVillain/BSUComputerGraphicsLabs/lab1/colors"
+)
+
+// Каталог с табличными (CLUT) профилями CMYK
+const profilesDir = "profiles"
+
+// CMYKProfileSpec - выбор профиля CMYK в запросе
+type CMYKProfileSpec struct {
+	Name            string  `json:"name"`                       // "naive" (по умолчанию), "gcr", "clut"
+	BlackGeneration float64 `json:"black_generation,omitempty"` // gcr: доля серой составляющей, заменяемая черной краской, 0..100%
+	InkLimit        float64 `json:"ink_limit,omitempty"`        // gcr: предельная сумма C+M+Y+K, % (0 - без ограничения)
+	File            string  `json:"file,omitempty"`             // clut: имя файла таблицы в каталоге profiles/
+}
+
+// newCMYKProfile создает профиль по описанию из запроса (nil - наивная формула)
+func newCMYKProfile(spec *CMYKProfileSpec) (colors.CMYKProfile, error) {
+	if spec == nil {
+		return colors.NaiveProfile{}, nil
+	}
+	switch spec.Name {
+	case "", "naive":
+		return colors.NaiveProfile{}, nil
+	case "gcr":
+		if spec.BlackGeneration < 0 || spec.BlackGeneration > 100 {
+			return nil, errors.New("profile: black_generation must be in 0..100")
+		}
+		if spec.InkLimit != 0 && (spec.InkLimit < 100 || spec.InkLimit > 400) {
+			return nil, errors.New("profile: ink_limit must be in 100..400")
+		}
+		return colors.GCRProfile{BlackGeneration: spec.BlackGeneration, InkLimit: spec.InkLimit}, nil
+	case "clut":
+		return loadCLUTProfile(spec.File)
+	}
+	return nil, errors.New("profile: name must be one of: naive, gcr, clut")
+}
+
+// addInkLimitClip отмечает превышение предельной суммы красок во входных значениях CMYK (в процентах)
+func addInkLimitClip(clip map[string]float64, spec *CMYKProfileSpec, c, m, y, k float64) {
+	if spec == nil || spec.Name != "gcr" || spec.InkLimit == 0 {
+		return
+	}
+	addInputClip(clip, "ink", c+m+y+k, 0, spec.InkLimit)
+}
+
+// ---------- Табличные профили (CLUT) ----------
+
+var (
+	clutCacheMu sync.Mutex
+	clutCache   = map[string]*colors.CLUTProfile{}
+)
+
+// loadCLUTProfile читает таблицу из каталога profiles/ (с кэшированием)
+func loadCLUTProfile(name string) (*colors.CLUTProfile, error) {
+	base := filepath.Base(name)
+	if name == "" || base != name || base == "." || base == ".." {
+		return nil, errors.New("profile: file must be a plain file name inside " + profilesDir + "/")
+	}
+
+	clutCacheMu.Lock()
+	defer clutCacheMu.Unlock()
+	if p, ok := clutCache[name]; ok {
+		return p, nil
+	}
+
+	f, err := os.Open(filepath.Join(profilesDir, name))
+	if err != nil {
+		return nil, fmt.Errorf("profile: %v", err)
+	}
+	defer f.Close()
+
+	p, err := colors.ParseCLUT(f)
+	if err != nil {
+		return nil, fmt.Errorf("profile %s: %v", name, err)
+	}
+	clutCache[name] = p
+	return p, nil
+}
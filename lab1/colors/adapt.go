@@ -0,0 +1,64 @@
+package colors
+
+// ---------- Белые точки и хроматическая адаптация ----------
+
+// Белые точки стандартных источников (2°, Y = 100), ASTM E308.
+// WhiteD65 и WhiteD50 объявлены в cie.go.
+var (
+	WhiteA   = XYZ{X: 109.850, Y: 100, Z: 35.585} // лампа накаливания, 2856 K
+	WhiteC   = XYZ{X: 98.074, Y: 100, Z: 118.232} // усредненный дневной свет (устаревший)
+	WhiteD55 = XYZ{X: 95.682, Y: 100, Z: 92.149}  // дневной свет, 5500 K
+	WhiteD75 = XYZ{X: 94.972, Y: 100, Z: 122.638} // северное небо, 7500 K
+	WhiteE   = XYZ{X: 100, Y: 100, Z: 100}        // равноэнергетический источник
+	WhiteF2  = XYZ{X: 99.187, Y: 100, Z: 67.395}  // холодная белая люминесцентная лампа
+	WhiteF7  = XYZ{X: 95.044, Y: 100, Z: 108.755} // люминесцентная лампа, имитирующая D65
+	WhiteF11 = XYZ{X: 100.966, Y: 100, Z: 64.370} // узкополосная люминесцентная лампа TL84
+)
+
+// Adaptation - модель хроматической адаптации
+type Adaptation int
+
+const (
+	Bradford   Adaptation = iota // линейная часть преобразования Бредфорда (как в ICC)
+	VonKries                     // фон Крис с колбочками Ханта-Пойнтера-Эстевеса
+	XYZScaling                   // масштабирование X, Y, Z - простейшая и наименее точная
+)
+
+// Матрицы перехода XYZ -> "колбочковое" пространство, в котором белые точки выравниваются покомпонентно
+var adaptationMatrices = map[Adaptation]matrix3{
+	Bradford: {
+		{0.8951, 0.2664, -0.1614},
+		{-0.7502, 1.7135, 0.0367},
+		{0.0389, -0.0685, 1.0296},
+	},
+	VonKries: {
+		{0.40024, 0.70760, -0.08081},
+		{-0.22630, 1.16532, 0.04570},
+		{0, 0, 0.91822},
+	},
+	XYZScaling: {
+		{1, 0, 0},
+		{0, 1, 0},
+		{0, 0, 1},
+	},
+}
+
+// adaptationMatrix - матрица, переводящая XYZ цвета, видимого при белом from,
+// в соответствующий цвет при белом to
+func adaptationMatrix(from, to XYZ, method Adaptation) matrix3 {
+	m := adaptationMatrices[method]
+	fa, fb, fc := m.apply(from.X, from.Y, from.Z)
+	ta, tb, tc := m.apply(to.X, to.Y, to.Z)
+	scale := matrix3{
+		{ta / fa, 0, 0},
+		{0, tb / fb, 0},
+		{0, 0, tc / fc},
+	}
+	return m.inverse().mul(scale.mul(m))
+}
+
+// Adapt - хроматическая адаптация XYZ от белой точки from к белой точке to
+func Adapt(c XYZ, from, to XYZ, method Adaptation) XYZ {
+	x, y, z := adaptationMatrix(from, to, method).apply(c.X, c.Y, c.Z)
+	return XYZ{X: x, Y: y, Z: z}
+}
@@ -0,0 +1,14 @@
+package colors
+
+// ---------- Прозрачность ----------
+
+// CompositeOver накладывает цвет c с непрозрачностью alpha (0..1) на непрозрачный фон bg.
+// Смешивание идет в гамма-кодированном sRGB (0..255), как это делают браузеры для CSS.
+func CompositeOver(c RGB, alpha float64, bg RGB) RGB {
+	alpha = clampFloat(alpha, 0, 1)
+	return RGB{
+		R: c.R*alpha + bg.R*(1-alpha),
+		G: c.G*alpha + bg.G*(1-alpha),
+		B: c.B*alpha + bg.B*(1-alpha),
+	}
+}
@@ -0,0 +1,169 @@
+package colors
+
+import "math"
+
+// ---------- Коррелированная цветовая температура ----------
+
+// Kelvin - цвет источника света: коррелированная цветовая температура (K, кельвины)
+// и отклонение от линии черного тела Duv в координатах CIE 1960 uv
+// (> 0 - выше линии, в сторону зеленого; < 0 - в сторону пурпурного).
+// Яркость не задается: RGB() возвращает самый яркий цвет такой цветности.
+type Kelvin struct {
+	K   float64 `json:"k"`
+	Duv float64 `json:"duv"`
+}
+
+// Диапазон температур, в котором работают аппроксимации линии черного тела
+const (
+	MinKelvin = 1667.0
+	MaxKelvin = 25000.0
+	// при |Duv| больше этого значения цвет уже не считается "белым", и CCT не имеет смысла
+	MaxDuv = 0.05
+)
+
+// CCTMethod - способ оценки цветовой температуры по цветности
+type CCTMethod int
+
+const (
+	Robertson CCTMethod = iota // изотермы Робертсона (1968), точность около 1 K
+	McCamy                     // кубическая формула МакКами (1992), ±2 K в диапазоне 2856..6504 K
+)
+
+func (c Kelvin) RGB() RGB {
+	u, v := kelvinUV(c)
+	x, y := uvToXY(u, v)
+	rl, gl, bl := xyzToSRGBMatrix.apply(x/y, 1, (1-x-y)/y)
+	// нормируем на самый яркий канал
+	m := math.Max(rl, math.Max(gl, bl))
+	return RGB{R: LinearToSRGB(rl/m) * 255, G: LinearToSRGB(gl/m) * 255, B: LinearToSRGB(bl/m) * 255}
+}
+
+// ToKelvin оценивает цветовую температуру цвета. ok = false, если цвет слишком далек
+// от линии черного тела (|Duv| > MaxDuv), температура вне MinKelvin..MaxKelvin или цвет черный.
+// Значение Kelvin возвращается как есть.
+func ToKelvin(c Color, method CCTMethod) (k Kelvin, ok bool) {
+	if k, isKelvin := c.(Kelvin); isKelvin {
+		return k, true
+	}
+	xyz := ToXYZ(c)
+	s := xyz.X + 15*xyz.Y + 3*xyz.Z
+	if s <= 0 || xyz.Y <= 0 {
+		return Kelvin{}, false
+	}
+	u, v := 4*xyz.X/s, 6*xyz.Y/s
+
+	switch method {
+	case McCamy:
+		x, y := uvToXY(u, v)
+		n := (x - 0.3320) / (0.1858 - y)
+		k.K = 449*n*n*n + 3525*n*n + 6823.3*n + 5520.33
+	default:
+		if k.K, ok = robertsonCCT(u, v); !ok {
+			return Kelvin{}, false
+		}
+	}
+	if k.K < MinKelvin || k.K > MaxKelvin {
+		return Kelvin{}, false
+	}
+
+	pu, pv := kelvinUV(Kelvin{K: k.K})
+	k.Duv = math.Hypot(u-pu, v-pv)
+	if v < pv {
+		k.Duv = -k.Duv
+	}
+	return k, math.Abs(k.Duv) <= MaxDuv
+}
+
+// kelvinUV - цветность (CIE 1960 uv) для температуры и Duv. Точка линии черного тела
+// и направление изотермы линейно интерполируются по таблице Робертсона в обратной температуре,
+// поэтому robertsonCCT восстанавливает исходную температуру. K ограничивается диапазоном MinKelvin..MaxKelvin.
+func kelvinUV(c Kelvin) (u, v float64) {
+	r := 1e6 / clampFloat(c.K, MinKelvin, MaxKelvin)
+	i := 1
+	for i < len(robertsonIsotherms)-1 && robertsonIsotherms[i].r < r {
+		i++
+	}
+	a, b := robertsonIsotherms[i-1], robertsonIsotherms[i]
+	f := (r - a.r) / (b.r - a.r)
+
+	// единичные векторы вдоль изотерм, направленные вверх (в сторону больших v)
+	dir := func(t float64) (float64, float64) {
+		n := math.Sqrt(1 + t*t)
+		return -1 / n, -t / n
+	}
+	au, av := dir(a.t)
+	bu, bv := dir(b.t)
+	du, dv := au+(bu-au)*f, av+(bv-av)*f
+	n := math.Hypot(du, dv)
+
+	u = a.u + (b.u-a.u)*f + c.Duv*du/n
+	v = a.v + (b.v-a.v)*f + c.Duv*dv/n
+	return u, v
+}
+
+func xyToUV(x, y float64) (u, v float64) {
+	d := -2*x + 12*y + 3
+	return 4 * x / d, 6 * y / d
+}
+
+func uvToXY(u, v float64) (x, y float64) {
+	d := 2*u - 8*v + 4
+	return 3 * u / d, 2 * v / d
+}
+
+// Изотермы Робертсона: обратная температура (мкК⁻¹) и точка (u, v) на линии черного тела
+// с наклоном изотермы t (Wyszecki, Stiles, "Color Science", табл. 1(3.11))
+var robertsonIsotherms = [...]struct{ r, u, v, t float64 }{
+	{0, 0.18006, 0.26352, -0.24341},
+	{10, 0.18066, 0.26589, -0.25479},
+	{20, 0.18133, 0.26846, -0.26876},
+	{30, 0.18208, 0.27119, -0.28539},
+	{40, 0.18293, 0.27407, -0.30470},
+	{50, 0.18388, 0.27709, -0.32675},
+	{60, 0.18494, 0.28021, -0.35156},
+	{70, 0.18611, 0.28342, -0.37915},
+	{80, 0.18740, 0.28668, -0.40955},
+	{90, 0.18880, 0.28997, -0.44278},
+	{100, 0.19032, 0.29326, -0.47888},
+	{125, 0.19462, 0.30141, -0.58204},
+	{150, 0.19962, 0.30921, -0.70471},
+	{175, 0.20525, 0.31647, -0.84901},
+	{200, 0.21142, 0.32312, -1.0182},
+	{225, 0.21807, 0.32909, -1.2168},
+	{250, 0.22511, 0.33439, -1.4512},
+	{275, 0.23247, 0.33904, -1.7298},
+	{300, 0.24010, 0.34308, -2.0637},
+	{325, 0.24792, 0.34655, -2.4681},
+	{350, 0.25591, 0.34951, -2.9641},
+	{375, 0.26400, 0.35200, -3.5814},
+	{400, 0.27218, 0.35407, -4.3633},
+	{425, 0.28039, 0.35577, -5.3762},
+	{450, 0.28863, 0.35714, -6.7262},
+	{475, 0.29685, 0.35823, -8.5955},
+	{500, 0.30505, 0.35907, -11.324},
+	{525, 0.31320, 0.35968, -15.628},
+	{550, 0.32129, 0.36011, -23.325},
+	{575, 0.32931, 0.36038, -40.770},
+	{600, 0.33724, 0.36051, -116.45},
+}
+
+// robertsonCCT ищет пару соседних изотерм, между которыми лежит точка (u, v),
+// и интерполирует обратную температуру по расстояниям до них
+func robertsonCCT(u, v float64) (float64, bool) {
+	prev := 0.0
+	for i, iso := range robertsonIsotherms {
+		// расстояние со знаком от точки до изотермы
+		d := ((v - iso.v) - iso.t*(u-iso.u)) / math.Sqrt(1+iso.t*iso.t)
+		if i > 0 && (d < 0) != (prev < 0) {
+			p := robertsonIsotherms[i-1]
+			f := prev / (prev - d)
+			r := p.r + (iso.r-p.r)*f
+			if r <= 0 {
+				return 0, false
+			}
+			return 1e6 / r, true
+		}
+		prev = d
+	}
+	return 0, false
+}
@@ -0,0 +1,81 @@
+package colors
+
+import (
+	"math"
+	"testing"
+)
+
+func TestKelvinKnownWhites(t *testing.T) {
+	cases := []struct {
+		white XYZ
+		k     float64
+	}{
+		{WhiteA, 2856},
+		{WhiteD50, 5003},
+		{WhiteD55, 5503},
+		{WhiteD65, 6504},
+		{WhiteD75, 7504},
+		{WhiteF2, 4230},
+	}
+	for _, c := range cases {
+		for _, m := range []CCTMethod{Robertson, McCamy} {
+			k, ok := ToKelvin(c.white, m)
+			if !ok || math.Abs(k.K-c.k) > 0.005*c.k {
+				t.Errorf("method %d: %v -> %+v (ok %v), want %v K", m, c.white, k, ok, c.k)
+			}
+		}
+	}
+	// дневные источники D лежат чуть выше линии черного тела
+	if k, _ := ToKelvin(WhiteD65, Robertson); math.Abs(k.Duv-0.0032) > 0.0005 {
+		t.Errorf("D65 Duv = %v, want ~0.0032", k.Duv)
+	}
+}
+
+func TestKelvinRoundTrip(t *testing.T) {
+	for k := MinKelvin; k <= MaxKelvin; k *= 1.1 {
+		for _, duv := range []float64{-0.02, 0, 0.02} {
+			// цвет в дробном RGB без обрезки, чтобы не мешал охват sRGB
+			rgb := Kelvin{K: k, Duv: duv}.RGB()
+			got, ok := ToKelvin(rgb, Robertson)
+			if !ok || math.Abs(got.K-k) > 0.002*k || math.Abs(got.Duv-duv) > 1e-4 {
+				t.Errorf("%v K, Duv %v -> %v -> %+v (ok %v)", k, duv, rgb, got, ok)
+			}
+		}
+	}
+	if _, ok := ToKelvin(RGB{R: 0, G: 255, B: 0}, Robertson); ok {
+		t.Error("pure green should have no CCT")
+	}
+	if _, ok := ToKelvin(RGB{}, Robertson); ok {
+		t.Error("black should have no CCT")
+	}
+}
+
+func TestAdapt(t *testing.T) {
+	// матрица Бредфорда D65 -> D50 из таблиц Линдблума (с его белыми точками)
+	lindbloomD50 := XYZ{X: 96.422, Y: 100, Z: 82.521}
+	bradford := matrix3{
+		{1.0478112, 0.0228866, -0.0501270},
+		{0.0295424, 0.9904844, -0.0170491},
+		{-0.0092345, 0.0150436, 0.7521316},
+	}
+	for _, c := range []XYZ{WhiteD65, {X: 20, Y: 30, Z: 40}, {X: 41.24, Y: 21.26, Z: 1.93}} {
+		x, y, z := bradford.apply(c.X, c.Y, c.Z)
+		if got := Adapt(c, WhiteD65, lindbloomD50, Bradford); xyzDist(got, XYZ{X: x, Y: y, Z: z}) > 1e-4 {
+			t.Errorf("Adapt(%v) = %v, want %v %v %v", c, got, x, y, z)
+		}
+	}
+	for _, m := range []Adaptation{Bradford, VonKries, XYZScaling} {
+		// белая точка переходит в белую точку, обратное преобразование возвращает исходный цвет
+		if got := Adapt(WhiteA, WhiteA, WhiteD65, m); xyzDist(got, WhiteD65) > 1e-9 {
+			t.Errorf("method %d: A -> D65 white = %v", m, got)
+		}
+		c := XYZ{X: 30, Y: 25, Z: 10}
+		if got := Adapt(Adapt(c, WhiteD65, WhiteF11, m), WhiteF11, WhiteD65, m); xyzDist(got, c) > 1e-9 {
+			t.Errorf("method %d: round trip %v -> %v", m, c, got)
+		}
+	}
+}
+
+func xyzDist(a, b XYZ) float64 {
+	return math.Max(math.Abs(a.X-b.X), math.Max(math.Abs(a.Y-b.Y), math.Abs(a.Z-b.Z)))
+}
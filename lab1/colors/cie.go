@@ -0,0 +1,225 @@
+package colors
+
+import "math"
+
+// Опорная белая точка D65 (2°), масштаб Y = 100
+const (
+	whiteX = 95.047
+	whiteY = 100.0
+	whiteZ = 108.883
+)
+
+// Белые точки D65 и D50 для LabFromXYZ и XYZFromLab
+var (
+	WhiteD65 = XYZ{X: whiteX, Y: whiteY, Z: whiteZ}
+	WhiteD50 = XYZ{X: whiteD50X, Y: whiteD50Y, Z: whiteD50Z}
+)
+
+// ---------- sRGB <-> CIE XYZ ----------
+
+var (
+	// матрица линейного sRGB -> XYZ (IEC 61966-2-1)
+	srgbToXYZMatrix = matrix3{
+		{0.4124564, 0.3575761, 0.1804375},
+		{0.2126729, 0.7151522, 0.0721750},
+		{0.0193339, 0.1191920, 0.9503041},
+	}
+	xyzToSRGBMatrix = srgbToXYZMatrix.inverse()
+)
+
+// RGB 0..255 -> XYZ (D65, Y 0..100)
+func RGBToXYZ(rInt, gInt, bInt int) (x, y, z float64) {
+	return rgbToXYZ(
+		clampFloat(float64(rInt), 0, 255),
+		clampFloat(float64(gInt), 0, 255),
+		clampFloat(float64(bInt), 0, 255),
+	)
+}
+
+// RGB (дробные 0..255, без обрезки) -> XYZ; нужен для цветов вне охвата sRGB
+func rgbToXYZ(r255, g255, b255 float64) (x, y, z float64) {
+	r := SRGBToLinear(r255 / 255.0)
+	g := SRGBToLinear(g255 / 255.0)
+	b := SRGBToLinear(b255 / 255.0)
+
+	x, y, z = srgbToXYZMatrix.apply(r, g, b)
+	return x * 100, y * 100, z * 100
+}
+
+// XYZ (D65, Y 0..100) -> RGB 0..255 (без обрезки, может выходить за диапазон)
+func XYZToRGB(x, y, z float64) (r, g, b float64) {
+	rl, gl, bl := xyzToSRGBMatrix.apply(x/100, y/100, z/100)
+
+	r = LinearToSRGB(rl) * 255.0
+	g = LinearToSRGB(gl) * 255.0
+	b = LinearToSRGB(bl) * 255.0
+	return
+}
+
+// ---------- CIE XYZ <-> CIELAB ----------
+
+// XYZ (D65) -> Lab: L 0..100, a/b примерно -128..127
+func XYZToLab(x, y, z float64) (l, a, b float64) {
+	return xyzToLabWhite(x, y, z, whiteX, whiteY, whiteZ)
+}
+
+// Lab -> XYZ (D65, Y 0..100)
+func LabToXYZ(l, a, b float64) (x, y, z float64) {
+	return labToXYZWhite(l, a, b, whiteX, whiteY, whiteZ)
+}
+
+// XYZ -> Lab относительно произвольной белой точки (wx, wy, wz)
+func xyzToLabWhite(x, y, z, wx, wy, wz float64) (l, a, b float64) {
+	fx := labF(x / wx)
+	fy := labF(y / wy)
+	fz := labF(z / wz)
+
+	l = 116*fy - 16
+	a = 500 * (fx - fy)
+	b = 200 * (fy - fz)
+	return
+}
+
+// LabFromXYZ - XYZ -> Lab относительно белой точки white
+func LabFromXYZ(c XYZ, white XYZ) Lab {
+	l, a, b := xyzToLabWhite(c.X, c.Y, c.Z, white.X, white.Y, white.Z)
+	return Lab{L: l, A: a, B: b}
+}
+
+// XYZFromLab - Lab (относительно белой точки white) -> XYZ
+func XYZFromLab(c Lab, white XYZ) XYZ {
+	x, y, z := labToXYZWhite(c.L, c.A, c.B, white.X, white.Y, white.Z)
+	return XYZ{X: x, Y: y, Z: z}
+}
+
+// Lab -> XYZ относительно произвольной белой точки (wx, wy, wz)
+func labToXYZWhite(l, a, b, wx, wy, wz float64) (x, y, z float64) {
+	fy := (l + 16) / 116
+	fx := fy + a/500
+	fz := fy - b/200
+
+	x = labFInv(fx) * wx
+	y = labFInv(fy) * wy
+	z = labFInv(fz) * wz
+	return
+}
+
+// RGB 0..255 -> Lab (через XYZ)
+func RGBToLab(rInt, gInt, bInt int) (l, a, b float64) {
+	return XYZToLab(RGBToXYZ(rInt, gInt, bInt))
+}
+
+// RGB (дробные 0..255, без обрезки) -> Lab
+func rgbToLab(r, g, b float64) (l, a, bb float64) {
+	return XYZToLab(rgbToXYZ(r, g, b))
+}
+
+// Lab -> RGB 0..255 (через XYZ, без обрезки)
+func LabToRGB(l, a, b float64) (r, g, bl float64) {
+	return XYZToRGB(LabToXYZ(l, a, b))
+}
+
+// ---------- D65 <-> D50 (CSS lab()/lch() заданы относительно D50) ----------
+
+// Белая точка D50 (x = 0.3457, y = 0.3585), как в CSS Color 4
+const (
+	whiteD50X = 96.42956764295677
+	whiteD50Y = 100.0
+	whiteD50Z = 82.51046025104602
+)
+
+// Бредфордская хроматическая адаптация D65 -> D50 (матрица из CSS Color 4)
+func xyzD65ToD50(x, y, z float64) (float64, float64, float64) {
+	return 1.0479297925449969*x + 0.022946870601609652*y - 0.05019226628920524*z,
+		0.02962780877005599*x + 0.9904344267538799*y - 0.017073799063418826*z,
+		-0.009243040646204504*x + 0.015055191490298152*y + 0.7518742814281371*z
+}
+
+// Бредфордская хроматическая адаптация D50 -> D65
+func xyzD50ToD65(x, y, z float64) (float64, float64, float64) {
+	return 0.955473421488075*x - 0.02309845494876471*y + 0.06325924320057072*z,
+		-0.0283697093338637*x + 1.0099953980813041*y + 0.021041441191917323*z,
+		0.012314014864481998*x - 0.020507649298898964*y + 1.330365926242124*z
+}
+
+// AdaptD65ToD50 - XYZ (D65) -> XYZ (D50)
+func AdaptD65ToD50(c XYZ) XYZ {
+	x, y, z := xyzD65ToD50(c.X, c.Y, c.Z)
+	return XYZ{X: x, Y: y, Z: z}
+}
+
+// AdaptD50ToD65 - XYZ (D50) -> XYZ (D65)
+func AdaptD50ToD65(c XYZ) XYZ {
+	x, y, z := xyzD50ToD65(c.X, c.Y, c.Z)
+	return XYZ{X: x, Y: y, Z: z}
+}
+
+// ---------- Вспомогательные ----------
+
+// SRGBToLinear - гамма-декодирование sRGB (0..1 -> линейное 0..1); знак сохраняется для значений вне диапазона
+func SRGBToLinear(v float64) float64 {
+	if math.Abs(v) <= 0.04045 {
+		return v / 12.92
+	}
+	if v < 0 {
+		return -math.Pow((-v+0.055)/1.055, 2.4)
+	}
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+// LinearToSRGB - гамма-кодирование sRGB (линейное -> 0..1); знак сохраняется для значений вне диапазона
+func LinearToSRGB(v float64) float64 {
+	if math.Abs(v) <= 0.0031308 {
+		return v * 12.92
+	}
+	if v < 0 {
+		return -(1.055*math.Pow(-v, 1/2.4) - 0.055)
+	}
+	return 1.055*math.Pow(v, 1/2.4) - 0.055
+}
+
+const (
+	labEpsilon = 216.0 / 24389.0
+	labKappa   = 24389.0 / 27.0
+)
+
+func labF(t float64) float64 {
+	if t > labEpsilon {
+		return math.Cbrt(t)
+	}
+	return (labKappa*t + 16) / 116
+}
+
+func labFInv(t float64) float64 {
+	if t3 := t * t * t; t3 > labEpsilon {
+		return t3
+	}
+	return (116*t - 16) / labKappa
+}
+
+// Lab -> LCh(ab): C - насыщенность, H - угол тона 0..360
+func labToLCh(l, a, b float64) (L, c, h float64) {
+	return l, math.Hypot(a, b), hueAngle(a, b)
+}
+
+// LCh(ab) -> Lab
+func lchToLab(l, c, h float64) (L, a, b float64) {
+	rad := degToRad(h)
+	return l, c * math.Cos(rad), c * math.Sin(rad)
+}
+
+// угол тона в градусах 0..360 по координатам a, b
+func hueAngle(a, b float64) float64 {
+	if a == 0 && b == 0 {
+		return 0
+	}
+	h := math.Atan2(b, a) * 180 / math.Pi
+	if h < 0 {
+		h += 360
+	}
+	return h
+}
+
+func degToRad(d float64) float64 {
+	return d * math.Pi / 180
+}
@@ -0,0 +1,258 @@
+package colors
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// CMYKProfile - способ перевода между RGB и CMYK
+type CMYKProfile interface {
+	ToRGB(c CMYK) RGB
+	FromRGB(c RGB) CMYK
+}
+
+// ProfiledCMYK - значения CMYK, переводимые в RGB профилем Profile.
+// ToCMYK возвращает их без изменений, остальные модели считаются через Profile.ToRGB.
+type ProfiledCMYK struct {
+	CMYK
+	Profile CMYKProfile
+}
+
+func (c ProfiledCMYK) RGB() RGB { return c.Profile.ToRGB(c.CMYK) }
+
+// ToCMYKProfile - цвет в CMYK по профилю p (значения CMYK и ProfiledCMYK возвращаются как есть)
+func ToCMYKProfile(c Color, p CMYKProfile) CMYK {
+	switch v := c.(type) {
+	case CMYK:
+		return v
+	case ProfiledCMYK:
+		return v.CMYK
+	}
+	return p.FromRGB(c.RGB())
+}
+
+// ---------- Наивная формула 1-K ----------
+
+// NaiveProfile - формула 1-K (то же, что CMYK.RGB и ToCMYK)
+type NaiveProfile struct{}
+
+func (NaiveProfile) ToRGB(c CMYK) RGB { return c.RGB() }
+
+func (NaiveProfile) FromRGB(c RGB) CMYK { return ToCMYK(c) }
+
+// ---------- UCR/GCR с ограничением суммы красок ----------
+
+// GCRProfile заменяет часть серой составляющей (min(C, M, Y)) черной краской.
+// BlackGeneration = 100 совпадает с наивной формулой, 0 - черная краска не используется.
+// При превышении InkLimit черная генерация усиливается, а если и этого мало -
+// цветные краски пропорционально уменьшаются.
+type GCRProfile struct {
+	BlackGeneration float64 // 0..100%
+	InkLimit        float64 // предельная сумма C+M+Y+K, 100..400%; 0 - без ограничения
+}
+
+func (p GCRProfile) ToRGB(c CMYK) RGB { return c.RGB() }
+
+func (p GCRProfile) FromRGB(in RGB) CMYK {
+	blackGen := p.BlackGeneration / 100
+	inkLimit := p.InkLimit / 100
+
+	cc := 1 - clampFloat(in.R/255.0, 0, 1)
+	mc := 1 - clampFloat(in.G/255.0, 0, 1)
+	yc := 1 - clampFloat(in.B/255.0, 0, 1)
+	gray := math.Min(cc, math.Min(mc, yc))
+	if almostEqual(gray, 1) {
+		return CMYK{K: 100}
+	}
+
+	separate := func(k float64) (c, m, y float64) {
+		return (cc - k) / (1 - k), (mc - k) / (1 - k), (yc - k) / (1 - k)
+	}
+	total := func(k float64) float64 {
+		c, m, y := separate(k)
+		return c + m + y + k
+	}
+	pct := func(c, m, y, k float64) CMYK {
+		return CMYK{C: c * 100, M: m * 100, Y: y * 100, K: k * 100}
+	}
+
+	k := gray * blackGen
+	if inkLimit > 0 && total(k) > inkLimit {
+		if total(gray) > inkLimit {
+			// даже полная замена серого не помогает - уменьшаем цветные краски
+			k = gray
+			c, m, y := separate(k)
+			scale := (inkLimit - k) / (c + m + y)
+			return pct(c*scale, m*scale, y*scale, k)
+		}
+		// бисекция между k (сумма выше предела) и gray (в пределе): hi всегда укладывается в предел,
+		// поэтому результат его не превышает. Сумма не обязательно монотонна по K, так что
+		// найденное K - граница, близкая к наименьшей, но не обязательно наименьшая
+		lo, hi := k, gray
+		for hi-lo > 1e-6 {
+			mid := (lo + hi) / 2
+			if total(mid) > inkLimit {
+				lo = mid
+			} else {
+				hi = mid
+			}
+		}
+		k = hi
+	}
+
+	c, m, y := separate(k)
+	return pct(clampFloat(c, 0, 1), clampFloat(m, 0, 1), clampFloat(y, 0, 1), k)
+}
+
+// ---------- Табличный профиль (CLUT) ----------
+
+// CLUTProfile - таблицы RGB -> CMYK (сетка n³) и CMYK -> RGB (сетка n⁴)
+// с много-линейной интерполяцией между узлами. Создается функцией ParseCLUT.
+//
+// Формат файла (текст, # - комментарий):
+//
+//	rgb2cmyk <n>
+//	<n³ строк "c m y k" в процентах; порядок узлов: R - внешний цикл, B - внутренний>
+//	cmyk2rgb <n>
+//	<n⁴ строк "r g b" (0..255); порядок узлов: C - внешний цикл, K - внутренний>
+type CLUTProfile struct {
+	rgbGrid  int
+	rgb2cmyk [][]float64
+	cmykGrid int
+	cmyk2rgb [][]float64
+}
+
+func (p *CLUTProfile) ToRGB(c CMYK) RGB {
+	out := interpolateGrid(p.cmyk2rgb, p.cmykGrid, []float64{
+		clampFloat(c.C/100, 0, 1),
+		clampFloat(c.M/100, 0, 1),
+		clampFloat(c.Y/100, 0, 1),
+		clampFloat(c.K/100, 0, 1),
+	})
+	return RGB{R: out[0], G: out[1], B: out[2]}
+}
+
+func (p *CLUTProfile) FromRGB(c RGB) CMYK {
+	out := interpolateGrid(p.rgb2cmyk, p.rgbGrid, []float64{
+		clampFloat(c.R/255, 0, 1),
+		clampFloat(c.G/255, 0, 1),
+		clampFloat(c.B/255, 0, 1),
+	})
+	return CMYK{
+		C: clampFloat(out[0], 0, 100),
+		M: clampFloat(out[1], 0, 100),
+		Y: clampFloat(out[2], 0, 100),
+		K: clampFloat(out[3], 0, 100),
+	}
+}
+
+// interpolateGrid - много-линейная интерполяция в равномерной сетке n^d (координаты 0..1).
+// Узлы хранятся построчно, первая координата меняется медленнее всех.
+func interpolateGrid(table [][]float64, n int, coords []float64) []float64 {
+	d := len(coords)
+	base := make([]int, d)
+	frac := make([]float64, d)
+	for i, v := range coords {
+		pos := v * float64(n-1)
+		base[i] = int(math.Floor(pos))
+		if base[i] >= n-1 {
+			base[i] = n - 2
+		}
+		frac[i] = pos - float64(base[i])
+	}
+
+	out := make([]float64, len(table[0]))
+	// обходим 2^d вершин ячейки
+	for corner := 0; corner < 1<<d; corner++ {
+		weight := 1.0
+		index := 0
+		for i := 0; i < d; i++ {
+			bit := corner >> (d - 1 - i) & 1
+			if bit == 1 {
+				weight *= frac[i]
+			} else {
+				weight *= 1 - frac[i]
+			}
+			index = index*n + base[i] + bit
+		}
+		if weight == 0 {
+			continue
+		}
+		for j, v := range table[index] {
+			out[j] += weight * v
+		}
+	}
+	return out
+}
+
+// ParseCLUT читает табличный профиль в формате, описанном у CLUTProfile
+func ParseCLUT(r io.Reader) (*CLUTProfile, error) {
+	p := &CLUTProfile{}
+	var (
+		cur   *[][]float64 // заполняемая таблица
+		want  int          // ожидаемое число строк
+		width int          // число значений в строке
+	)
+
+	sc := bufio.NewScanner(r)
+	for lineNo := 1; sc.Scan(); lineNo++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+
+		if fields[0] == "rgb2cmyk" || fields[0] == "cmyk2rgb" {
+			if cur != nil && len(*cur) != want {
+				return nil, fmt.Errorf("line %d: previous table has %d rows, want %d", lineNo, len(*cur), want)
+			}
+			if len(fields) != 2 {
+				return nil, fmt.Errorf("line %d: expected %q <grid size>", lineNo, fields[0])
+			}
+			n, err := strconv.Atoi(fields[1])
+			if err != nil || n < 2 || n > 64 {
+				return nil, fmt.Errorf("line %d: grid size must be 2..64", lineNo)
+			}
+			if fields[0] == "rgb2cmyk" {
+				p.rgbGrid, cur, want, width = n, &p.rgb2cmyk, n*n*n, 4
+			} else {
+				p.cmykGrid, cur, want, width = n, &p.cmyk2rgb, n*n*n*n, 3
+			}
+			continue
+		}
+
+		if cur == nil {
+			return nil, fmt.Errorf("line %d: data before table header", lineNo)
+		}
+		if len(*cur) >= want {
+			return nil, fmt.Errorf("line %d: too many rows", lineNo)
+		}
+		if len(fields) != width {
+			return nil, fmt.Errorf("line %d: expected %d values", lineNo, width)
+		}
+		row := make([]float64, width)
+		for i, fs := range fields {
+			v, err := strconv.ParseFloat(fs, 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", lineNo, err)
+			}
+			row[i] = v
+		}
+		*cur = append(*cur, row)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if cur != nil && len(*cur) != want {
+		return nil, fmt.Errorf("table has %d rows, want %d", len(*cur), want)
+	}
+	if p.rgb2cmyk == nil || p.cmyk2rgb == nil {
+		return nil, errors.New("both rgb2cmyk and cmyk2rgb tables are required")
+	}
+	return p, nil
+}
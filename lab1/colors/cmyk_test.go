@@ -0,0 +1,81 @@
+package colors
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestGCRProfile(t *testing.T) {
+	naive := NaiveProfile{}
+	full := GCRProfile{BlackGeneration: 100}
+	none := GCRProfile{BlackGeneration: 0}
+	limited := GCRProfile{BlackGeneration: 50, InkLimit: 240}
+
+	forEachRGB(17, func(c RGB) {
+		// полная черная генерация совпадает с наивной формулой
+		if a, b := naive.FromRGB(c), full.FromRGB(c); !cmykEqual(a, b, 1e-9) {
+			t.Fatalf("%v: gcr 100%% = %v, naive = %v", c, b, a)
+		}
+		// без черной краски K появляется только у чистого черного
+		if k := none.FromRGB(c).K; k != 0 && c != (RGB{}) {
+			t.Fatalf("%v: gcr 0%% gave K = %v", c, k)
+		}
+		// сумма красок не превышает предел, а цвет сохраняется, пока в пределе хватает черной
+		p := limited.FromRGB(c)
+		if sum := p.C + p.M + p.Y + p.K; sum > 240+1e-6 {
+			t.Fatalf("%v: ink sum %.3f > 240 (%v)", c, sum, p)
+		}
+		if full := naive.FromRGB(c); full.C+full.M+full.Y+full.K <= 240 {
+			if e := rgbError(c, limited.ToRGB(p)); e > 1e-6 {
+				t.Fatalf("%v: %v -> %v (error %g)", c, p, limited.ToRGB(p), e)
+			}
+		}
+	})
+}
+
+func cmykEqual(a, b CMYK, eps float64) bool {
+	return math.Abs(a.C-b.C) <= eps && math.Abs(a.M-b.M) <= eps &&
+		math.Abs(a.Y-b.Y) <= eps && math.Abs(a.K-b.K) <= eps
+}
+
+func TestParseCLUT(t *testing.T) {
+	// сетка 2: в узлах - значения наивной формулы, между ними - интерполяция
+	var sb strings.Builder
+	sb.WriteString("# test\nrgb2cmyk 2\n")
+	for i := 0; i < 8; i++ {
+		c := ToCMYK(RGB{R: float64(i>>2&1) * 255, G: float64(i>>1&1) * 255, B: float64(i&1) * 255})
+		fmt.Fprintf(&sb, "%g %g %g %g\n", c.C, c.M, c.Y, c.K)
+	}
+	sb.WriteString("cmyk2rgb 2\n")
+	for i := 0; i < 16; i++ {
+		c := CMYK{C: float64(i>>3&1) * 100, M: float64(i>>2&1) * 100, Y: float64(i>>1&1) * 100, K: float64(i&1) * 100}
+		p := c.RGB()
+		fmt.Fprintf(&sb, "%g %g %g\n", p.R, p.G, p.B)
+	}
+
+	p, err := ParseCLUT(strings.NewReader(sb.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := p.FromRGB(RGB{R: 255}); !cmykEqual(got, CMYK{M: 100, Y: 100}, 1e-9) {
+		t.Errorf("red -> %v", got)
+	}
+	if got := p.ToRGB(CMYK{K: 50}); rgbError(got, RGB{R: 127.5, G: 127.5, B: 127.5}) > 1e-9 {
+		t.Errorf("K=50 -> %v", got)
+	}
+
+	bad := []string{
+		"",
+		"1 2 3 4\n",
+		"rgb2cmyk 1\n",
+		"rgb2cmyk 2\n0 0 0 0\n",
+		"rgb2cmyk 2\n" + strings.Repeat("0 0 0\n", 8),
+	}
+	for _, s := range bad {
+		if _, err := ParseCLUT(strings.NewReader(s)); err == nil {
+			t.Errorf("ParseCLUT(%q): expected an error", s)
+		}
+	}
+}
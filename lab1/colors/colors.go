@@ -0,0 +1,261 @@
+// Package colors - цветовые модели и преобразования между ними:
+// sRGB и широкие RGB-пространства, CMYK, HSV, HSL, HWB, HSI, CIE XYZ, CIELAB, CIE LCh, OKLab, OKLCh,
+// видеомодели YCbCr, YUV, YIQ, линейный RGB и HDR-сигналы PQ и HLG,
+// а также цветовые различия, приведение в охват sRGB, контраст, симуляция
+// нарушений цветового зрения, расчет XYZ по спектру отражения и смешение цветов.
+//
+// Каждая модель представлена отдельным типом. Все типы реализуют интерфейс Color,
+// поэтому любой цвет можно перевести в любую модель функциями ToRGB, ToHSV, ToLab и т.д.
+//
+//	lab := colors.ToLab(colors.HSV{H: 30, S: 100, V: 100})
+//	rgb := colors.ToRGB(lab) // ≈ colors.RGB{R: 255, G: 127.5, B: 0}
+//
+// Результат дробный: ToRGB не округляет каналы до целых, для вывода есть методы Rounded.
+// Значения не обрезаются: цвет вне охвата sRGB (например, насыщенный Lab)
+// дает RGB за пределами 0..255. Привести его в охват можно функциями ClipToGamut,
+// ReduceChroma или NearestByDeltaE.
+package colors
+
+// Color - цвет в любой из моделей пакета
+type Color interface {
+	// RGB возвращает цвет в sRGB (0..255, без обрезки)
+	RGB() RGB
+}
+
+// RGB - sRGB, каналы 0..255 (дробные; вне охвата могут выходить за диапазон)
+type RGB struct {
+	R float64 `json:"r"`
+	G float64 `json:"g"`
+	B float64 `json:"b"`
+}
+
+// CMYK - C, M, Y, K в процентах 0..100 (наивная формула 1-K; см. также CMYKProfile)
+type CMYK struct {
+	C float64 `json:"c"`
+	M float64 `json:"m"`
+	Y float64 `json:"y"`
+	K float64 `json:"k"`
+}
+
+// HSV - H 0..360, S и V в процентах
+type HSV struct {
+	H float64 `json:"h"`
+	S float64 `json:"s"`
+	V float64 `json:"v"`
+}
+
+// HSL - H 0..360, S и L в процентах
+type HSL struct {
+	H float64 `json:"h"`
+	S float64 `json:"s"`
+	L float64 `json:"l"`
+}
+
+// HWB - H 0..360, W (белизна) и B (чернота) в процентах
+type HWB struct {
+	H float64 `json:"h"`
+	W float64 `json:"w"`
+	B float64 `json:"b"`
+}
+
+// HSI - H 0..360, S и I (интенсивность) в процентах
+type HSI struct {
+	H float64 `json:"h"`
+	S float64 `json:"s"`
+	I float64 `json:"i"`
+}
+
+// XYZ - CIE XYZ (D65), Y белого = 100
+type XYZ struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+	Z float64 `json:"z"`
+}
+
+// Lab - CIELAB (D65): L 0..100, a/b примерно -128..127
+type Lab struct {
+	L float64 `json:"l"`
+	A float64 `json:"a"`
+	B float64 `json:"b"`
+}
+
+// LCh - цилиндрическая форма CIELAB: L 0..100, C (насыщенность) от 0, H 0..360
+type LCh struct {
+	L float64 `json:"l"`
+	C float64 `json:"c"`
+	H float64 `json:"h"`
+}
+
+// OKLab - OKLab: L 0..1, a/b примерно -0.4..0.4
+type OKLab struct {
+	L float64 `json:"l"`
+	A float64 `json:"a"`
+	B float64 `json:"b"`
+}
+
+// OKLCh - OKLCh: L 0..1, C примерно 0..0.4, H 0..360
+type OKLCh struct {
+	L float64 `json:"l"`
+	C float64 `json:"c"`
+	H float64 `json:"h"`
+}
+
+// ---------- Color ----------
+
+func (c RGB) RGB() RGB { return c }
+
+func (c CMYK) RGB() RGB { return rgb(CMYKToRGB(c.C, c.M, c.Y, c.K)) }
+
+func (c HSV) RGB() RGB { return rgb(HSVToRGB(c.H, c.S, c.V)) }
+
+func (c HSL) RGB() RGB { return rgb(HSLToRGB(c.H, c.S, c.L)) }
+
+func (c HWB) RGB() RGB { return rgb(HWBToRGB(c.H, c.W, c.B)) }
+
+func (c HSI) RGB() RGB { return rgb(HSIToRGB(c.H, c.S, c.I)) }
+
+func (c XYZ) RGB() RGB { return rgb(XYZToRGB(c.X, c.Y, c.Z)) }
+
+func (c Lab) RGB() RGB { return rgb(LabToRGB(c.L, c.A, c.B)) }
+
+func (c LCh) RGB() RGB { return ToLab(c).RGB() }
+
+func (c OKLab) RGB() RGB { return rgb(OKLabToRGB(c.L, c.A, c.B)) }
+
+func (c OKLCh) RGB() RGB { return rgb(OKLChToRGB(c.L, c.C, c.H)) }
+
+func rgb(r, g, b float64) RGB { return RGB{R: r, G: g, B: b} }
+
+func hsv(h, s, v float64) HSV { return HSV{H: h, S: s, V: v} }
+
+// ---------- Преобразования между моделями ----------
+//
+// Если цвет уже в нужной модели, он возвращается без изменений.
+// Группы HSV/HSL/HWB, XYZ/Lab/LCh и OKLab/OKLCh переводятся внутри себя напрямую
+// (в частности, тон ахроматических цветов сохраняется), остальные - через sRGB.
+
+func ToRGB(c Color) RGB { return c.RGB() }
+
+// ToCMYK - наивная формула 1-K; каналы RGB вне 0..255 обрезаются
+func ToCMYK(c Color) CMYK {
+	switch v := c.(type) {
+	case CMYK:
+		return v
+	case ProfiledCMYK:
+		return v.CMYK
+	}
+	p := c.RGB()
+	cc, m, y, k := rgbToCMYK(p.R, p.G, p.B)
+	return CMYK{C: cc * 100, M: m * 100, Y: y * 100, K: k * 100}
+}
+
+// ToHSV - каналы RGB вне 0..255 обрезаются
+func ToHSV(c Color) HSV {
+	switch v := c.(type) {
+	case HSV:
+		return v
+	case HSL:
+		return hsv(hslToHSV(v.H, v.S, v.L))
+	case HWB:
+		return hsv(hwbToHSV(v.H, v.W, v.B))
+	}
+	p := c.RGB()
+	h, s, v := rgbToHSV(p.R, p.G, p.B)
+	return HSV{H: h, S: s * 100, V: v * 100}
+}
+
+// ToHSL - каналы RGB вне 0..255 обрезаются
+func ToHSL(c Color) HSL {
+	switch v := c.(type) {
+	case HSL:
+		return v
+	case HSV, HWB:
+		hv := ToHSV(v)
+		h, s, l := hsvToHSL(hv.H, hv.S, hv.V)
+		return HSL{H: h, S: s, L: l}
+	}
+	p := c.RGB()
+	h, s, l := rgbToHSL(p.R, p.G, p.B)
+	return HSL{H: h, S: s * 100, L: l * 100}
+}
+
+// ToHWB - каналы RGB вне 0..255 обрезаются
+func ToHWB(c Color) HWB {
+	switch v := c.(type) {
+	case HWB:
+		return v
+	case HSV, HSL:
+		hv := ToHSV(v)
+		h, w, b := hsvToHWB(hv.H, hv.S, hv.V)
+		return HWB{H: h, W: w, B: b}
+	}
+	p := c.RGB()
+	h, w, b := rgbToHWB(p.R, p.G, p.B)
+	return HWB{H: h, W: w * 100, B: b * 100}
+}
+
+// ToHSI - каналы RGB вне 0..255 обрезаются
+func ToHSI(c Color) HSI {
+	if v, ok := c.(HSI); ok {
+		return v
+	}
+	p := c.RGB()
+	h, s, i := rgbToHSI(p.R, p.G, p.B)
+	return HSI{H: h, S: s * 100, I: i * 100}
+}
+
+func ToXYZ(c Color) XYZ {
+	switch v := c.(type) {
+	case XYZ:
+		return v
+	case Lab:
+		return XYZFromLab(v, WhiteD65)
+	case LCh:
+		return XYZFromLab(ToLab(v), WhiteD65)
+	}
+	p := c.RGB()
+	x, y, z := rgbToXYZ(p.R, p.G, p.B)
+	return XYZ{X: x, Y: y, Z: z}
+}
+
+func ToLab(c Color) Lab {
+	switch v := c.(type) {
+	case Lab:
+		return v
+	case LCh:
+		l, a, b := lchToLab(v.L, v.C, v.H)
+		return Lab{L: l, A: a, B: b}
+	}
+	return LabFromXYZ(ToXYZ(c), WhiteD65)
+}
+
+func ToLCh(c Color) LCh {
+	if v, ok := c.(LCh); ok {
+		return v
+	}
+	lab := ToLab(c)
+	l, ch, h := labToLCh(lab.L, lab.A, lab.B)
+	return LCh{L: l, C: ch, H: h}
+}
+
+func ToOKLab(c Color) OKLab {
+	switch v := c.(type) {
+	case OKLab:
+		return v
+	case OKLCh:
+		l, a, b := OKLChToOKLab(v.L, v.C, v.H)
+		return OKLab{L: l, A: a, B: b}
+	}
+	p := c.RGB()
+	l, a, b := rgbToOKLab(p.R, p.G, p.B)
+	return OKLab{L: l, A: a, B: b}
+}
+
+func ToOKLCh(c Color) OKLCh {
+	if v, ok := c.(OKLCh); ok {
+		return v
+	}
+	lab := ToOKLab(c)
+	l, ch, h := OKLabToOKLCh(lab.L, lab.A, lab.B)
+	return OKLCh{L: l, C: ch, H: h}
+}
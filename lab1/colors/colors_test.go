@@ -0,0 +1,230 @@
+package colors
+
+import (
+	"flag"
+	"math"
+	"testing"
+)
+
+// По умолчанию свойства проверяются на сетке RGB с шагом 5 (52³ цвета),
+// с флагом -exhaustive - на всех 16.7 млн цветах (несколько минут):
+//
+//	go test ./colors -run RoundTrip -exhaustive -timeout 30m
+var exhaustive = flag.Bool("exhaustive", false, "проверять все 256³ цветов RGB вместо сетки")
+
+// model - модель и ее преобразования для табличных тестов
+type model struct {
+	name    string
+	to      func(Color) Color // перевод в модель без округления
+	rounded func(Color) Color // перевод с округлением, как в ответе сервера
+}
+
+var models = []model{
+	{"rgb", func(c Color) Color { return ToRGB(c) }, func(c Color) Color { return ToRGB(c).Rounded() }},
+	{"cmyk", func(c Color) Color { return ToCMYK(c) }, func(c Color) Color { return ToCMYK(c).Rounded() }},
+	{"hsv", func(c Color) Color { return ToHSV(c) }, func(c Color) Color { return ToHSV(c).Rounded() }},
+	{"hsl", func(c Color) Color { return ToHSL(c) }, func(c Color) Color { return ToHSL(c).Rounded() }},
+	{"hwb", func(c Color) Color { return ToHWB(c) }, func(c Color) Color { return ToHWB(c).Rounded() }},
+	{"hsi", func(c Color) Color { return ToHSI(c) }, func(c Color) Color { return ToHSI(c).Rounded() }},
+	{"xyz", func(c Color) Color { return ToXYZ(c) }, func(c Color) Color { return ToXYZ(c).Rounded() }},
+	{"lab", func(c Color) Color { return ToLab(c) }, func(c Color) Color { return ToLab(c).Rounded() }},
+	{"lch", func(c Color) Color { return ToLCh(c) }, func(c Color) Color { return ToLCh(c).Rounded() }},
+	{"oklab", func(c Color) Color { return ToOKLab(c) }, func(c Color) Color { return ToOKLab(c).Rounded() }},
+	{"oklch", func(c Color) Color { return ToOKLCh(c) }, func(c Color) Color { return ToOKLCh(c).Rounded() }},
+}
+
+// Допустимая ошибка перевода RGB -> X -> RGB без округления (единицы 0..255).
+// Гарантия из Readme.md; фактическая ошибка на 1-2 порядка меньше.
+const floatBound = 1e-9
+
+// Допустимая ошибка RGB -> X -> Y -> X -> RGB при округлении на каждом шаге,
+// по исходной модели X (максимум по всем Y, с запасом). Совпадает с таблицей в Readme.md.
+var pairBounds = map[string]float64{
+	"rgb":   0,
+	"cmyk":  0.45,
+	"hsv":   0.45,
+	"hsl":   0.45,
+	"hwb":   0.45,
+	"hsi":   0.45,
+	"xyz":   0.5,
+	"lab":   0.6,
+	"lch":   0.7,
+	"oklab": 0.5,
+	"oklch": 0.5,
+}
+
+// forEachRGB обходит 8-битные цвета: всю решетку или сетку с шагом step (с границей 255)
+func forEachRGB(step int, fn func(c RGB)) {
+	if *exhaustive {
+		step = 1
+	}
+	values := []int{}
+	for v := 0; v < 255; v += step {
+		values = append(values, v)
+	}
+	values = append(values, 255)
+	for _, r := range values {
+		for _, g := range values {
+			for _, b := range values {
+				fn(RGB{R: float64(r), G: float64(g), B: float64(b)})
+			}
+		}
+	}
+}
+
+// rgbError - наибольшее отклонение по каналам
+func rgbError(a, b RGB) float64 {
+	return math.Max(math.Abs(a.R-b.R), math.Max(math.Abs(a.G-b.G), math.Abs(a.B-b.B)))
+}
+
+func TestRoundTripFloat(t *testing.T) {
+	for _, m := range models {
+		t.Run(m.name, func(t *testing.T) {
+			worst, at := 0.0, RGB{}
+			forEachRGB(5, func(c RGB) {
+				if e := rgbError(c, m.to(c).RGB()); e > worst {
+					worst, at = e, c
+				}
+			})
+			if worst > floatBound {
+				t.Errorf("rgb -> %s -> rgb: error %g at %v, want <= %g", m.name, worst, at, floatBound)
+			}
+		})
+	}
+}
+
+// Главная гарантия: значения, которые отдает сервер, переводятся обратно в тот же 8-битный RGB
+func TestRoundTripRounded(t *testing.T) {
+	for _, m := range models {
+		t.Run(m.name, func(t *testing.T) {
+			worst, at := 0.0, RGB{}
+			forEachRGB(5, func(c RGB) {
+				if e := rgbError(c, m.rounded(c).RGB()); e > worst {
+					worst, at = e, c
+				}
+			})
+			if worst >= 0.5 {
+				t.Errorf("rgb -> %s (rounded) -> rgb: error %.4f at %v, want < 0.5", m.name, worst, at)
+			}
+		})
+	}
+}
+
+func TestRoundTripPairs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("slow")
+	}
+	for _, x := range models {
+		for _, y := range models {
+			t.Run(x.name+"-"+y.name, func(t *testing.T) {
+				worstFloat, worstRounded := 0.0, 0.0
+				forEachRGB(15, func(c RGB) {
+					worstFloat = math.Max(worstFloat, rgbError(c, x.to(y.to(x.to(c))).RGB()))
+					worstRounded = math.Max(worstRounded, rgbError(c, x.rounded(y.rounded(x.rounded(c))).RGB()))
+				})
+				if worstFloat > floatBound {
+					t.Errorf("float error %g, want <= %g", worstFloat, floatBound)
+				}
+				if bound := pairBounds[x.name]; worstRounded > bound {
+					t.Errorf("rounded error %.4f, want <= %g", worstRounded, bound)
+				}
+			})
+		}
+	}
+}
+
+// Перевод в собственную модель - тождественный, в том числе для вырожденных значений
+func TestIdentity(t *testing.T) {
+	cases := []Color{
+		RGB{R: 300, G: -20, B: 12.5},
+		CMYK{C: 30, M: 30, Y: 30, K: 100},
+		HSV{H: 200, S: 0, V: 50},
+		HSL{H: 123.456, S: 50, L: 100},
+		HWB{H: 77, W: 60, B: 60},
+		HSI{H: 10, S: 0, I: 0},
+		XYZ{X: 1, Y: 2, Z: 3},
+		Lab{L: 50, A: 200, B: -200},
+		LCh{L: 50, C: 0, H: 270},
+		OKLab{L: 0.5, A: 0.1, B: -0.1},
+		OKLCh{L: 0.7, C: 0, H: 45},
+	}
+	convert := map[string]func(Color) Color{}
+	for _, m := range models {
+		convert[m.name] = m.to
+	}
+	names := []string{"rgb", "cmyk", "hsv", "hsl", "hwb", "hsi", "xyz", "lab", "lch", "oklab", "oklch"}
+	for i, c := range cases {
+		if got := convert[names[i]](c); got != c {
+			t.Errorf("%s(%v) = %v, want the same value", names[i], c, got)
+		}
+	}
+}
+
+// Внутри групп HSV/HSL/HWB, Lab/LCh и OKLab/OKLCh тон серого не теряется
+func TestHuePreserved(t *testing.T) {
+	gray := HSV{H: 200, S: 0, V: 50}
+	if h := ToHSL(gray).H; h != 200 {
+		t.Errorf("hsl hue = %v, want 200", h)
+	}
+	if h := ToHWB(ToHSL(gray)).H; h != 200 {
+		t.Errorf("hwb hue = %v, want 200", h)
+	}
+	if h := ToHSV(HWB{H: 33, W: 70, B: 70}).H; h != 33 {
+		t.Errorf("hsv hue = %v, want 33", h)
+	}
+	if h := ToHSV(gray).RGB(); h.R != h.G || h.G != h.B {
+		t.Errorf("gray rgb = %v, want equal channels", h)
+	}
+}
+
+func TestKnownValues(t *testing.T) {
+	approx := func(a, b, eps float64) bool { return math.Abs(a-b) <= eps }
+
+	white := RGB{R: 255, G: 255, B: 255}
+	if xyz := ToXYZ(white); !approx(xyz.X, 95.047, 0.01) || !approx(xyz.Y, 100, 1e-4) || !approx(xyz.Z, 108.883, 0.01) {
+		t.Errorf("white xyz = %v", xyz)
+	}
+	if lab := ToLab(white); !approx(lab.L, 100, 1e-4) || !approx(lab.A, 0, 0.01) || !approx(lab.B, 0, 0.01) {
+		t.Errorf("white lab = %v", lab)
+	}
+	// значения из статьи Ottosson
+	if ok := ToOKLab(white); !approx(ok.L, 1, 1e-4) || !approx(ok.A, 0, 1e-4) || !approx(ok.B, 0, 1e-4) {
+		t.Errorf("white oklab = %v", ok)
+	}
+	red := RGB{R: 255}
+	if lab := ToLab(red); !approx(lab.L, 53.24, 0.01) || !approx(lab.A, 80.09, 0.01) || !approx(lab.B, 67.20, 0.01) {
+		t.Errorf("red lab = %v", lab)
+	}
+	if ok := ToOKLCh(red); !approx(ok.L, 0.62796, 1e-4) || !approx(ok.C, 0.25768, 1e-4) || !approx(ok.H, 29.23, 0.01) {
+		t.Errorf("red oklch = %v", ok)
+	}
+	if c := ToCMYK(RGB{R: 255, G: 128}); !approx(c.C, 0, 1e-9) || !approx(c.M, 49.8, 0.01) || !approx(c.Y, 100, 1e-9) || c.K != 0 {
+		t.Errorf("orange cmyk = %v", c)
+	}
+	if h := ToHSV(RGB{R: 0, G: 128, B: 255}); !approx(h.H, 209.88, 0.01) || h.S != 100 || h.V != 100 {
+		t.Errorf("azure hsv = %v", h)
+	}
+}
+
+// FuzzRoundTrip: для любого цвета внутри куба sRGB перевод в любую модель и обратно
+// (без округления) возвращает исходный цвет
+func FuzzRoundTrip(f *testing.F) {
+	f.Add(0.0, 0.0, 0.0)
+	f.Add(255.0, 255.0, 255.0)
+	f.Add(250.0, 0.0, 0.0)
+	f.Add(10.3, 10.3, 10.31)
+	f.Add(0.5, 254.5, 128.25)
+	f.Fuzz(func(t *testing.T, r, g, b float64) {
+		for _, v := range []float64{r, g, b} {
+			if math.IsNaN(v) || v < 0 || v > 255 {
+				t.Skip()
+			}
+		}
+		c := RGB{R: r, G: g, B: b}
+		for _, m := range models {
+			if e := rgbError(c, m.to(c).RGB()); e > floatBound {
+				t.Errorf("rgb -> %s -> rgb: %v -> %v (error %g)", m.name, c, m.to(c).RGB(), e)
+			}
+		}
+	})
+}
@@ -0,0 +1,74 @@
+package colors
+
+import "math"
+
+// ---------- Формулы контраста ----------
+
+// WCAGContrast - коэффициент контраста WCAG 2.x для двух цветов (1..21)
+func WCAGContrast(c1, c2 Color) float64 {
+	l1 := RelativeLuminance(c1)
+	l2 := RelativeLuminance(c2)
+	if l1 < l2 {
+		l1, l2 = l2, l1
+	}
+	return (l1 + 0.05) / (l2 + 0.05)
+}
+
+// RelativeLuminance - относительная яркость по WCAG (линейный sRGB, коэффициенты Rec. 709), 0..1
+func RelativeLuminance(c Color) float64 {
+	p := c.RGB()
+	return 0.2126*SRGBToLinear(p.R/255) + 0.7152*SRGBToLinear(p.G/255) + 0.0722*SRGBToLinear(p.B/255)
+}
+
+// APCAContrast - контраст APCA (версия 0.0.98G-4g) текста txt на фоне bg.
+// Возвращает Lc примерно в диапазоне -108..106:
+// > 0 - темный текст на светлом фоне, < 0 - наоборот.
+func APCAContrast(txt, bg Color) float64 {
+	const (
+		mainTRC   = 2.4
+		normBG    = 0.56
+		normTXT   = 0.57
+		revTXT    = 0.62
+		revBG     = 0.65
+		blkThrs   = 0.022
+		blkClmp   = 1.414
+		scale     = 1.14
+		loOffset  = 0.027
+		loClip    = 0.1
+		deltaYmin = 0.0005
+	)
+
+	screenY := func(c Color) float64 {
+		p := c.RGB()
+		y := 0.2126729*math.Pow(clampFloat(p.R/255, 0, 1), mainTRC) +
+			0.7151522*math.Pow(clampFloat(p.G/255, 0, 1), mainTRC) +
+			0.0721750*math.Pow(clampFloat(p.B/255, 0, 1), mainTRC)
+		// мягкое ограничение снизу для очень темных цветов
+		if y < blkThrs {
+			y += math.Pow(blkThrs-y, blkClmp)
+		}
+		return y
+	}
+
+	yTxt := screenY(txt)
+	yBg := screenY(bg)
+	if math.Abs(yBg-yTxt) < deltaYmin {
+		return 0
+	}
+
+	var out float64
+	if yBg > yTxt {
+		// темный текст на светлом фоне
+		sapc := (math.Pow(yBg, normBG) - math.Pow(yTxt, normTXT)) * scale
+		if sapc >= loClip {
+			out = sapc - loOffset
+		}
+	} else {
+		// светлый текст на темном фоне
+		sapc := (math.Pow(yBg, revBG) - math.Pow(yTxt, revTXT)) * scale
+		if sapc <= -loClip {
+			out = sapc + loOffset
+		}
+	}
+	return out * 100
+}
@@ -0,0 +1,46 @@
+package colors
+
+import (
+	"math"
+	"testing"
+)
+
+func TestWCAGContrast(t *testing.T) {
+	black, white := RGB{}, RGB{R: 255, G: 255, B: 255}
+	cases := []struct {
+		a, b RGB
+		want float64
+	}{
+		{black, white, 21},
+		{white, black, 21},
+		{white, white, 1},
+		{RGB{R: 119, G: 119, B: 119}, white, 4.48},
+		{RGB{R: 0, G: 0, B: 255}, white, 8.59},
+	}
+	for _, c := range cases {
+		if got := WCAGContrast(c.a, c.b); math.Abs(got-c.want) > 0.005 {
+			t.Errorf("WCAGContrast(%v, %v) = %.3f, want %.2f", c.a, c.b, got, c.want)
+		}
+	}
+}
+
+// Значения из эталонной реализации APCA 0.0.98G-4g
+func TestAPCAContrast(t *testing.T) {
+	black, white := RGB{}, RGB{R: 255, G: 255, B: 255}
+	gray := RGB{R: 136, G: 136, B: 136}
+	cases := []struct {
+		txt, bg RGB
+		want    float64
+	}{
+		{black, white, 106.04},
+		{white, black, -107.88},
+		{gray, white, 63.06},
+		{white, gray, -68.54},
+		{white, white, 0},
+	}
+	for _, c := range cases {
+		if got := APCAContrast(c.txt, c.bg); math.Abs(got-c.want) > 0.01 {
+			t.Errorf("APCAContrast(%v, %v) = %.3f, want %.2f", c.txt, c.bg, got, c.want)
+		}
+	}
+}
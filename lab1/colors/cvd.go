@@ -0,0 +1,83 @@
+package colors
+
+// ---------- Нарушения цветового зрения ----------
+
+// Deficiency - вид нарушения цветового зрения
+type Deficiency int
+
+const (
+	Protanopia    Deficiency = iota // нет L-колбочек (красный)
+	Deuteranopia                    // нет M-колбочек (зеленый)
+	Tritanopia                      // нет S-колбочек (синий)
+	Achromatopsia                   // остается только яркость
+)
+
+// Матрицы Machado, Oliveira, Fernandes (2009) для полной дихромазии (severity = 1),
+// применяются к линейному sRGB. Промежуточные степени получаются линейной
+// интерполяцией с единичной матрицей.
+var (
+	protanopiaMatrix = [3][3]float64{
+		{0.152286, 1.052583, -0.204868},
+		{0.114503, 0.786281, 0.099216},
+		{-0.003882, -0.048116, 1.051998},
+	}
+	deuteranopiaMatrix = [3][3]float64{
+		{0.367322, 0.860646, -0.227968},
+		{0.280085, 0.672501, 0.047413},
+		{-0.011820, 0.042940, 0.968881},
+	}
+	tritanopiaMatrix = [3][3]float64{
+		{1.255528, -0.076749, -0.178779},
+		{-0.078411, 0.930809, 0.147602},
+		{0.004733, 0.691367, 0.303900},
+	}
+	// ахроматопсия: каждый канал заменяется яркостью Y
+	achromatopsiaMatrix = [3][3]float64{
+		{0.2126729, 0.7151522, 0.0721750},
+		{0.2126729, 0.7151522, 0.0721750},
+		{0.2126729, 0.7151522, 0.0721750},
+	}
+)
+
+// SimulateCVD - как цвет c видит человек с нарушением d степени severity (0..1, 1 - полная дихромазия).
+// Результат может немного выходить за охват sRGB.
+func SimulateCVD(c Color, d Deficiency, severity float64) RGB {
+	p := c.RGB()
+	r := SRGBToLinear(p.R / 255)
+	g := SRGBToLinear(p.G / 255)
+	b := SRGBToLinear(p.B / 255)
+
+	var m [3][3]float64
+	switch d {
+	case Protanopia:
+		m = protanopiaMatrix
+	case Deuteranopia:
+		m = deuteranopiaMatrix
+	case Tritanopia:
+		m = tritanopiaMatrix
+	default:
+		m = achromatopsiaMatrix
+	}
+	m = blendWithIdentity(m, clampFloat(severity, 0, 1))
+
+	return RGB{
+		R: LinearToSRGB(m[0][0]*r+m[0][1]*g+m[0][2]*b) * 255,
+		G: LinearToSRGB(m[1][0]*r+m[1][1]*g+m[1][2]*b) * 255,
+		B: LinearToSRGB(m[2][0]*r+m[2][1]*g+m[2][2]*b) * 255,
+	}
+}
+
+// blendWithIdentity - (1 - t) * E + t * m
+func blendWithIdentity(m [3][3]float64, t float64) [3][3]float64 {
+	var out [3][3]float64
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			id := 0.0
+			if i == j {
+				id = 1
+			}
+			out[i][j] = id + (m[i][j]-id)*t
+		}
+	}
+	return out
+}
@@ -0,0 +1,107 @@
+package colors
+
+import "math"
+
+// ---------- Цветовые различия ----------
+
+// DeltaE76 - ΔE*76, евклидово расстояние в Lab
+func DeltaE76(x1, x2 Lab) float64 {
+	return math.Sqrt((x1.L-x2.L)*(x1.L-x2.L) + (x1.A-x2.A)*(x1.A-x2.A) + (x1.B-x2.B)*(x1.B-x2.B))
+}
+
+// DeltaE94 - ΔE*94 (коэффициенты для полиграфии: kL = 1, K1 = 0.045, K2 = 0.015).
+// Формула несимметрична: первый цвет считается эталоном.
+func DeltaE94(x1, x2 Lab) float64 {
+	const kL, k1, k2 = 1.0, 0.045, 0.015
+
+	c1 := math.Hypot(x1.A, x1.B)
+	c2 := math.Hypot(x2.A, x2.B)
+	dL := x1.L - x2.L
+	dC := c1 - c2
+	da := x1.A - x2.A
+	db := x1.B - x2.B
+	// ΔH² = Δa² + Δb² - ΔC²; из-за погрешностей может стать чуть меньше нуля
+	dH2 := math.Max(da*da+db*db-dC*dC, 0)
+
+	sL := 1.0
+	sC := 1 + k1*c1
+	sH := 1 + k2*c1
+
+	return math.Sqrt(
+		(dL/(kL*sL))*(dL/(kL*sL)) +
+			(dC/sC)*(dC/sC) +
+			dH2/(sH*sH))
+}
+
+// DeltaE2000 - CIEDE2000 (kL = kC = kH = 1), по статье Sharma, Wu, Dalal (2005)
+func DeltaE2000(x1, x2 Lab) float64 {
+	const pow25to7 = 6103515625.0 // 25^7
+
+	l1, a1, b1 := x1.L, x1.A, x1.B
+	l2, a2, b2 := x2.L, x2.A, x2.B
+
+	c1 := math.Hypot(a1, b1)
+	c2 := math.Hypot(a2, b2)
+	cMean := (c1 + c2) / 2
+	cMean7 := math.Pow(cMean, 7)
+	g := 0.5 * (1 - math.Sqrt(cMean7/(cMean7+pow25to7)))
+
+	a1p := (1 + g) * a1
+	a2p := (1 + g) * a2
+	c1p := math.Hypot(a1p, b1)
+	c2p := math.Hypot(a2p, b2)
+	h1p := hueAngle(a1p, b1)
+	h2p := hueAngle(a2p, b2)
+
+	dLp := l2 - l1
+	dCp := c2p - c1p
+
+	var dhp float64
+	switch {
+	case c1p*c2p == 0:
+		dhp = 0
+	case math.Abs(h2p-h1p) <= 180:
+		dhp = h2p - h1p
+	case h2p-h1p > 180:
+		dhp = h2p - h1p - 360
+	default:
+		dhp = h2p - h1p + 360
+	}
+	dHp := 2 * math.Sqrt(c1p*c2p) * math.Sin(degToRad(dhp/2))
+
+	lMean := (l1 + l2) / 2
+	cpMean := (c1p + c2p) / 2
+
+	var hpMean float64
+	switch {
+	case c1p*c2p == 0:
+		hpMean = h1p + h2p
+	case math.Abs(h1p-h2p) <= 180:
+		hpMean = (h1p + h2p) / 2
+	case h1p+h2p < 360:
+		hpMean = (h1p + h2p + 360) / 2
+	default:
+		hpMean = (h1p + h2p - 360) / 2
+	}
+
+	t := 1 -
+		0.17*math.Cos(degToRad(hpMean-30)) +
+		0.24*math.Cos(degToRad(2*hpMean)) +
+		0.32*math.Cos(degToRad(3*hpMean+6)) -
+		0.20*math.Cos(degToRad(4*hpMean-63))
+
+	dTheta := 30 * math.Exp(-((hpMean-275)/25)*((hpMean-275)/25))
+	cpMean7 := math.Pow(cpMean, 7)
+	rC := 2 * math.Sqrt(cpMean7/(cpMean7+pow25to7))
+	lm50 := (lMean - 50) * (lMean - 50)
+	sL := 1 + 0.015*lm50/math.Sqrt(20+lm50)
+	sC := 1 + 0.045*cpMean
+	sH := 1 + 0.015*cpMean*t
+	rT := -math.Sin(degToRad(2*dTheta)) * rC
+
+	return math.Sqrt(
+		(dLp/sL)*(dLp/sL) +
+			(dCp/sC)*(dCp/sC) +
+			(dHp/sH)*(dHp/sH) +
+			rT*(dCp/sC)*(dHp/sH))
+}
@@ -0,0 +1,44 @@
+package colors
+
+import (
+	"math"
+	"testing"
+)
+
+// Пары из статьи Sharma, Wu, Dalal (2005), таблица 1
+func TestDeltaE2000Sharma(t *testing.T) {
+	cases := []struct {
+		a, b Lab
+		want float64
+	}{
+		{Lab{50, 2.6772, -79.7751}, Lab{50, 0, -82.7485}, 2.0425},
+		{Lab{50, 3.1571, -77.2803}, Lab{50, 0, -82.7485}, 2.8615},
+		{Lab{50, 2.8361, -74.0200}, Lab{50, 0, -82.7485}, 3.4412},
+		{Lab{50, 0, 0}, Lab{50, -1, 2}, 2.3669},
+		{Lab{50, 2.49, -0.001}, Lab{50, -2.49, 0.0009}, 7.1792},
+		{Lab{50, 2.5, 0}, Lab{73, 25, -18}, 27.1492},
+		{Lab{60.2574, -34.0099, 36.2677}, Lab{60.4626, -34.1751, 39.4387}, 1.2644},
+	}
+	for _, c := range cases {
+		if got := DeltaE2000(c.a, c.b); math.Abs(got-c.want) > 1e-4 {
+			t.Errorf("DeltaE2000(%v, %v) = %.4f, want %.4f", c.a, c.b, got, c.want)
+		}
+		if got := DeltaE2000(c.b, c.a); math.Abs(got-c.want) > 1e-4 {
+			t.Errorf("DeltaE2000 is not symmetric for %v, %v: %.4f", c.a, c.b, got)
+		}
+	}
+}
+
+func TestDeltaE76And94(t *testing.T) {
+	a, b := Lab{50, 0, 0}, Lab{53, 4, 0}
+	if got := DeltaE76(a, b); got != 5 {
+		t.Errorf("DeltaE76 = %v, want 5", got)
+	}
+	// у ахроматического эталона SC = SH = 1, и ΔE94 совпадает с ΔE76
+	if got := DeltaE94(a, b); math.Abs(got-5) > 1e-12 {
+		t.Errorf("DeltaE94 = %v, want 5", got)
+	}
+	if DeltaE94(b, a) >= DeltaE94(a, b) {
+		t.Errorf("DeltaE94 with a chromatic reference should be smaller")
+	}
+}
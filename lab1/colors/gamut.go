@@ -0,0 +1,76 @@
+package colors
+
+// ---------- Приведение в охват sRGB ----------
+
+// GamutTolerance: значение, которое после округления всё равно попадает в 0..255, считается внутри охвата
+const GamutTolerance = 0.5
+
+// Цель поиска для ReduceChroma и NearestByDeltaE - точность в единицах RGB 0..255
+const gamutSearchPrecision = 0.01
+
+// InGamut проверяет, что цвет лежит внутри куба sRGB (с допуском GamutTolerance)
+func InGamut(c Color) bool {
+	p := c.RGB()
+	lo, hi := -GamutTolerance, 255+GamutTolerance
+	return p.R >= lo && p.R <= hi && p.G >= lo && p.G <= hi && p.B >= lo && p.B <= hi
+}
+
+// ClipToGamut обрезает каналы RGB до 0..255
+func ClipToGamut(c Color) RGB {
+	p := c.RGB()
+	return RGB{R: clampFloat(p.R, 0, 255), G: clampFloat(p.G, 0, 255), B: clampFloat(p.B, 0, 255)}
+}
+
+// ReduceChroma - уменьшение насыщенности в LCh при сохранении светлоты и тона.
+// Светлота за пределами 0..100 сначала приводится к границе (там охват вырождается в точку).
+func ReduceChroma(c Color) RGB {
+	if InGamut(c) {
+		return ClipToGamut(c)
+	}
+	lch := ToLCh(c.RGB())
+	lch.L = clampFloat(lch.L, 0, 100)
+
+	// бинарный поиск наибольшей насыщенности, при которой цвет еще в охвате
+	lo, hi := 0.0, lch.C
+	for hi-lo > gamutSearchPrecision {
+		mid := (lo + hi) / 2
+		if InGamut(LCh{L: lch.L, C: mid, H: lch.H}) {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return ClipToGamut(LCh{L: lch.L, C: lo, H: lch.H})
+}
+
+// NearestByDeltaE - ближайший по ΔE*76 цвет внутри куба sRGB.
+// Поиск по образцу (pattern search), начиная с обрезанного цвета, с уменьшением шага.
+func NearestByDeltaE(c Color) RGB {
+	if InGamut(c) {
+		return ClipToGamut(c)
+	}
+	target := ToLab(c.RGB())
+	dist := func(p [3]float64) float64 {
+		return DeltaE76(target, ToLab(RGB{R: p[0], G: p[1], B: p[2]}))
+	}
+
+	clipped := ClipToGamut(c)
+	best := [3]float64{clipped.R, clipped.G, clipped.B}
+	bestDist := dist(best)
+
+	for step := 32.0; step > gamutSearchPrecision; step /= 2 {
+		for improved := true; improved; {
+			improved = false
+			for ch := 0; ch < 3; ch++ {
+				for _, dir := range []float64{-1, 1} {
+					cand := best
+					cand[ch] = clampFloat(cand[ch]+dir*step, 0, 255)
+					if d := dist(cand); d < bestDist {
+						best, bestDist, improved = cand, d, true
+					}
+				}
+			}
+		}
+	}
+	return RGB{R: best[0], G: best[1], B: best[2]}
+}
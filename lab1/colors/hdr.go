@@ -0,0 +1,177 @@
+package colors
+
+import "math"
+
+// ---------- Линейный RGB и HDR: PQ, HLG ----------
+//
+// Линейные значения относительные: 1 - белый SDR, больше 1 - ярче белого (HDR).
+// Для перевода в абсолютную яркость белый SDR считается равным HDRReferenceWhite
+// (рекомендация ITU-R BT.2408), т.е. сигнал PQ 0.58 или HLG 0.75.
+
+const (
+	HDRReferenceWhite = 203.0   // яркость белого SDR, кд/м²
+	PQPeak            = 10000.0 // яркость при сигнале PQ = 1, кд/м²
+	HLGPeak           = 1000.0  // номинальная яркость дисплея HLG, кд/м²
+)
+
+// LinearRGB - линейные каналы пространства Space (по умолчанию sRGB), без обрезки
+type LinearRGB struct {
+	R float64 `json:"r"`
+	G float64 `json:"g"`
+	B float64 `json:"b"`
+
+	Space *RGBSpace `json:"-"`
+}
+
+// PQ - сигнал SMPTE ST 2084 (0..1) в пространстве Space (по умолчанию Rec.2020, как в BT.2100)
+type PQ struct {
+	R float64 `json:"r"`
+	G float64 `json:"g"`
+	B float64 `json:"b"`
+
+	Space *RGBSpace `json:"-"`
+}
+
+// HLG - сигнал ARIB STD-B67 / BT.2100 HLG (0..1) для дисплея HLGPeak
+// в пространстве Space (по умолчанию Rec.2020)
+type HLG struct {
+	R float64 `json:"r"`
+	G float64 `json:"g"`
+	B float64 `json:"b"`
+
+	Space *RGBSpace `json:"-"`
+}
+
+func spaceOr(s, def *RGBSpace) *RGBSpace {
+	if s == nil {
+		return def
+	}
+	return s
+}
+
+func (c LinearRGB) RGB() RGB { return spaceOr(c.Space, SRGB).linearToRGB(c.R, c.G, c.B) }
+
+func (c PQ) RGB() RGB {
+	return spaceOr(c.Space, Rec2020).linearToRGB(
+		PQToNits(c.R)/HDRReferenceWhite, PQToNits(c.G)/HDRReferenceWhite, PQToNits(c.B)/HDRReferenceWhite)
+}
+
+func (c HLG) RGB() RGB {
+	s := spaceOr(c.Space, Rec2020)
+	r, g, b := hlgToDisplay(s, c.R, c.G, c.B)
+	return s.linearToRGB(r/HDRReferenceWhite, g/HDRReferenceWhite, b/HDRReferenceWhite)
+}
+
+// ToLinearRGB - линейные каналы в пространстве s (nil - sRGB)
+func ToLinearRGB(c Color, s *RGBSpace) LinearRGB {
+	s = spaceOr(s, SRGB)
+	if v, ok := c.(LinearRGB); ok && spaceOr(v.Space, SRGB) == s {
+		return v
+	}
+	r, g, b := s.linearFromRGB(c.RGB())
+	return LinearRGB{R: r, G: g, B: b, Space: s}
+}
+
+// ToPQ - сигнал PQ в пространстве s (nil - Rec.2020)
+func ToPQ(c Color, s *RGBSpace) PQ {
+	s = spaceOr(s, Rec2020)
+	if v, ok := c.(PQ); ok && spaceOr(v.Space, Rec2020) == s {
+		return v
+	}
+	r, g, b := s.linearFromRGB(c.RGB())
+	return PQ{
+		R:     NitsToPQ(r * HDRReferenceWhite),
+		G:     NitsToPQ(g * HDRReferenceWhite),
+		B:     NitsToPQ(b * HDRReferenceWhite),
+		Space: s,
+	}
+}
+
+// ToHLG - сигнал HLG в пространстве s (nil - Rec.2020)
+func ToHLG(c Color, s *RGBSpace) HLG {
+	s = spaceOr(s, Rec2020)
+	if v, ok := c.(HLG); ok && spaceOr(v.Space, Rec2020) == s {
+		return v
+	}
+	r, g, b := s.linearFromRGB(c.RGB())
+	r, g, b = hlgFromDisplay(s, r*HDRReferenceWhite, g*HDRReferenceWhite, b*HDRReferenceWhite)
+	return HLG{R: r, G: g, B: b, Space: s}
+}
+
+// ---------- PQ (SMPTE ST 2084) ----------
+
+const (
+	pqM1 = 2610.0 / 16384
+	pqM2 = 2523.0 / 4096 * 128
+	pqC1 = 3424.0 / 4096
+	pqC2 = 2413.0 / 4096 * 32
+	pqC3 = 2392.0 / 4096 * 32
+)
+
+// PQToNits - EOTF: сигнал 0..1 -> яркость 0..10000 кд/м² (знак сохраняется).
+// Модуль сигнала больше 1 считается за 1: выше кривая не определена.
+func PQToNits(e float64) float64 {
+	p := math.Pow(math.Min(math.Abs(e), 1), 1/pqM2)
+	y := math.Pow(math.Max(p-pqC1, 0)/(pqC2-pqC3*p), 1/pqM1)
+	return math.Copysign(y*PQPeak, e)
+}
+
+// NitsToPQ - обратная EOTF: яркость кд/м² -> сигнал 0..1 (знак сохраняется)
+func NitsToPQ(nits float64) float64 {
+	y := math.Pow(math.Abs(nits)/PQPeak, pqM1)
+	return math.Copysign(math.Pow((pqC1+pqC2*y)/(1+pqC3*y), pqM2), nits)
+}
+
+// ---------- HLG (BT.2100) ----------
+
+const (
+	hlgA     = 0.17883277
+	hlgB     = 0.28466892 // 1 - 4a
+	hlgC     = 0.55991073 // 0.5 - a·ln(4a)
+	hlgGamma = 1.2        // системная гамма для дисплея 1000 кд/м²
+)
+
+// HLGOETF - линейный свет сцены 0..1 -> сигнал 0..1 (знак сохраняется)
+func HLGOETF(e float64) float64 {
+	a := math.Abs(e)
+	if a <= 1.0/12 {
+		return math.Copysign(math.Sqrt(3*a), e)
+	}
+	return math.Copysign(hlgA*math.Log(12*a-hlgB)+hlgC, e)
+}
+
+// HLGInverseOETF - сигнал 0..1 -> линейный свет сцены 0..1 (знак сохраняется)
+func HLGInverseOETF(s float64) float64 {
+	a := math.Abs(s)
+	if a <= 0.5 {
+		return math.Copysign(a*a/3, s)
+	}
+	return math.Copysign((math.Exp((a-hlgC)/hlgA)+hlgB)/12, s)
+}
+
+// hlgLuminance - яркость по строке Y матрицы пространства (для Rec.2020 - коэффициенты BT.2100)
+func hlgLuminance(s *RGBSpace, r, g, b float64) float64 {
+	return s.toXYZ[1][0]*r + s.toXYZ[1][1]*g + s.toXYZ[1][2]*b
+}
+
+// hlgToDisplay - сигнал HLG -> свет дисплея (кд/м²): обратная OETF и OOTF Fd = Lw·Ys^(γ-1)·E
+func hlgToDisplay(s *RGBSpace, r, g, b float64) (float64, float64, float64) {
+	r, g, b = HLGInverseOETF(r), HLGInverseOETF(g), HLGInverseOETF(b)
+	ys := hlgLuminance(s, r, g, b)
+	if ys <= 0 {
+		return 0, 0, 0
+	}
+	k := HLGPeak * math.Pow(ys, hlgGamma-1)
+	return k * r, k * g, k * b
+}
+
+// hlgFromDisplay - обратное к hlgToDisplay: яркость дисплея Yd = Lw·Ys^γ, отсюда Ys и E
+func hlgFromDisplay(s *RGBSpace, r, g, b float64) (float64, float64, float64) {
+	yd := hlgLuminance(s, r, g, b)
+	if yd <= 0 {
+		return 0, 0, 0
+	}
+	ys := math.Pow(yd/HLGPeak, 1/hlgGamma)
+	k := HLGPeak * math.Pow(ys, hlgGamma-1)
+	return HLGOETF(r / k), HLGOETF(g / k), HLGOETF(b / k)
+}
@@ -0,0 +1,71 @@
+package colors
+
+import (
+	"math"
+	"testing"
+)
+
+func TestHDRRoundTrip(t *testing.T) {
+	models := []model{
+		{"linear", func(c Color) Color { return ToLinearRGB(c, nil) }, func(c Color) Color { return ToLinearRGB(c, nil).Rounded() }},
+		{"linear-p3", func(c Color) Color { return ToLinearRGB(c, DisplayP3) }, func(c Color) Color { return ToLinearRGB(c, DisplayP3).Rounded() }},
+		{"pq", func(c Color) Color { return ToPQ(c, nil) }, func(c Color) Color { return ToPQ(c, nil).Rounded() }},
+		{"hlg", func(c Color) Color { return ToHLG(c, nil) }, func(c Color) Color { return ToHLG(c, nil).Rounded() }},
+		{"hlg-p3", func(c Color) Color { return ToHLG(c, DisplayP3) }, func(c Color) Color { return ToHLG(c, DisplayP3).Rounded() }},
+	}
+	for _, m := range models {
+		worstFloat, worstRounded := 0.0, 0.0
+		forEachRGB(5, func(c RGB) {
+			worstFloat = math.Max(worstFloat, rgbError(c, m.to(c).RGB()))
+			worstRounded = math.Max(worstRounded, rgbError(c, m.rounded(c).RGB()))
+		})
+		if worstFloat > floatBound {
+			t.Errorf("%s: float error %g, want <= %g", m.name, worstFloat, floatBound)
+		}
+		if worstRounded >= 0.5 {
+			t.Errorf("%s: rounded error %.4f, want < 0.5", m.name, worstRounded)
+		}
+	}
+}
+
+func TestHDRKnownValues(t *testing.T) {
+	approx := func(a, b, eps float64) bool { return math.Abs(a-b) <= eps }
+
+	// опорные точки ST 2084 и BT.2100
+	if v := NitsToPQ(10000); !approx(v, 1, 1e-12) {
+		t.Errorf("pq(10000) = %v", v)
+	}
+	if v := NitsToPQ(100); !approx(v, 0.5081, 1e-4) {
+		t.Errorf("pq(100) = %v", v)
+	}
+	if v := PQToNits(NitsToPQ(1234.5)); !approx(v, 1234.5, 1e-9) {
+		t.Errorf("pq round trip = %v", v)
+	}
+	if v := HLGOETF(1); !approx(v, 1, 1e-6) {
+		t.Errorf("hlg oetf(1) = %v", v)
+	}
+	if v := HLGOETF(1.0 / 12); !approx(v, 0.5, 1e-12) {
+		t.Errorf("hlg oetf(1/12) = %v", v)
+	}
+
+	// белый SDR: PQ 0.58, HLG 0.75 (BT.2408)
+	white := RGB{R: 255, G: 255, B: 255}
+	if pq := ToPQ(white, nil); !approx(pq.R, 0.5807, 1e-3) || !approx(pq.G, pq.R, 1e-6) {
+		t.Errorf("white pq = %+v", pq)
+	}
+	if hlg := ToHLG(white, nil); !approx(hlg.R, 0.75, 1e-3) || !approx(hlg.B, hlg.R, 1e-6) {
+		t.Errorf("white hlg = %+v", hlg)
+	}
+	if lin := ToLinearRGB(RGB{R: 128, G: 128, B: 128}, nil); !approx(lin.R, 0.2158605, 1e-6) {
+		t.Errorf("gray linear = %+v", lin)
+	}
+
+	// HDR: линейное 4.0 вне охвата SDR, но представимо в PQ
+	bright := LinearRGB{R: 4, G: 4, B: 4}
+	if InGamut(bright) {
+		t.Error("linear 4.0 is inside sRGB")
+	}
+	if pq := ToPQ(bright, nil); !approx(PQToNits(pq.G), 4*HDRReferenceWhite, 0.01) {
+		t.Errorf("bright pq = %+v", pq)
+	}
+}
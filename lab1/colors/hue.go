@@ -0,0 +1,172 @@
+package colors
+
+import "math"
+
+// ---------- Цилиндрические модели: HSL, HWB, HSI ----------
+
+// RGB 0..255 -> HSL: H 0..360, S 0..1, L 0..1
+func RGBToHSL(rInt, gInt, bInt int) (h, s, l float64) {
+	return rgbToHSL(float64(rInt), float64(gInt), float64(bInt))
+}
+
+// RGB (дробные 0..255) -> HSL: H 0..360, S 0..1, L 0..1
+func rgbToHSL(r255, g255, b255 float64) (h, s, l float64) {
+	r := clampFloat(r255/255.0, 0, 1)
+	g := clampFloat(g255/255.0, 0, 1)
+	b := clampFloat(b255/255.0, 0, 1)
+
+	max := math.Max(r, math.Max(g, b))
+	min := math.Min(r, math.Min(g, b))
+	delta := max - min
+
+	// тон совпадает с HSV
+	h, _, _ = rgbToHSV(r255, g255, b255)
+
+	l = (max + min) / 2
+	if almostEqual(delta, 0) {
+		s = 0
+	} else {
+		s = delta / (1 - math.Abs(2*l-1))
+	}
+	return
+}
+
+// HSL -> RGB (inputs: H 0..360, S 0..100, L 0..100) -> RGB 0..255
+func HSLToRGB(hDeg, sPct, lPct float64) (r, g, b float64) {
+	return HSVToRGB(hslToHSV(hDeg, sPct, lPct))
+}
+
+// HSL -> HSV (все в процентах); тон переносится без изменений
+func hslToHSV(hDeg, sPct, lPct float64) (h, sv, v float64) {
+	s := clampFloat(sPct/100.0, 0, 1)
+	l := clampFloat(lPct/100.0, 0, 1)
+
+	// V = L + S*min(L, 1-L), S_v = 2*(1 - L/V)
+	v = l + s*math.Min(l, 1-l)
+	if !almostEqual(v, 0) {
+		sv = 2 * (1 - l/v)
+	}
+	return hDeg, sv * 100, v * 100
+}
+
+// HSV -> HSL (все в процентах); тон переносится без изменений
+func hsvToHSL(hDeg, sPct, vPct float64) (h, sl, l float64) {
+	s := clampFloat(sPct/100.0, 0, 1)
+	v := clampFloat(vPct/100.0, 0, 1)
+
+	// L = V*(1 - S/2), S_l = (V - L)/min(L, 1-L)
+	l = v * (1 - s/2)
+	if m := math.Min(l, 1-l); !almostEqual(m, 0) {
+		sl = (v - l) / m
+	}
+	return hDeg, sl * 100, l * 100
+}
+
+// RGB 0..255 -> HWB: H 0..360, W 0..1, B 0..1
+func RGBToHWB(rInt, gInt, bInt int) (h, w, bl float64) {
+	return rgbToHWB(float64(rInt), float64(gInt), float64(bInt))
+}
+
+// RGB (дробные 0..255) -> HWB: H 0..360, W 0..1, B 0..1
+func rgbToHWB(r255, g255, b255 float64) (h, w, bl float64) {
+	r := clampFloat(r255/255.0, 0, 1)
+	g := clampFloat(g255/255.0, 0, 1)
+	b := clampFloat(b255/255.0, 0, 1)
+
+	h, _, _ = rgbToHSV(r255, g255, b255)
+	w = math.Min(r, math.Min(g, b))
+	bl = 1 - math.Max(r, math.Max(g, b))
+	return
+}
+
+// HWB -> RGB (inputs: H 0..360, W 0..100, B 0..100) -> RGB 0..255
+func HWBToRGB(hDeg, wPct, bPct float64) (r, g, b float64) {
+	return HSVToRGB(hwbToHSV(hDeg, wPct, bPct))
+}
+
+// HWB -> HSV (все в процентах); тон переносится без изменений
+func hwbToHSV(hDeg, wPct, bPct float64) (h, s, v float64) {
+	w := clampFloat(wPct/100.0, 0, 1)
+	bl := clampFloat(bPct/100.0, 0, 1)
+
+	// при W + B >= 1 получается серый (как в CSS Color 4)
+	if w+bl >= 1 {
+		return hDeg, 0, w / (w + bl) * 100
+	}
+
+	v = 1 - bl
+	s = 1 - w/v
+	return hDeg, s * 100, v * 100
+}
+
+// HSV -> HWB (все в процентах); тон переносится без изменений
+func hsvToHWB(hDeg, sPct, vPct float64) (h, w, b float64) {
+	s := clampFloat(sPct/100.0, 0, 1)
+	v := clampFloat(vPct/100.0, 0, 1)
+	return hDeg, (1 - s) * v * 100, (1 - v) * 100
+}
+
+// RGB 0..255 -> HSI: H 0..360, S 0..1, I 0..1
+func RGBToHSI(rInt, gInt, bInt int) (h, s, i float64) {
+	return rgbToHSI(float64(rInt), float64(gInt), float64(bInt))
+}
+
+// RGB (дробные 0..255) -> HSI: H 0..360, S 0..1, I 0..1
+func rgbToHSI(r255, g255, b255 float64) (h, s, i float64) {
+	r := clampFloat(r255/255.0, 0, 1)
+	g := clampFloat(g255/255.0, 0, 1)
+	b := clampFloat(b255/255.0, 0, 1)
+
+	i = (r + g + b) / 3
+	min := math.Min(r, math.Min(g, b))
+	if almostEqual(i, 0) {
+		return 0, 0, 0
+	}
+	s = 1 - min/i
+
+	// геометрическое определение тона (Гонсалес, Вудс): угол проекции на плоскость
+	// хроматичности. Вместо arccos(num/den) используется равносильный atan2 -
+	// arccos теряет точность около 0° и 180°.
+	x := 2*r - g - b
+	y := math.Sqrt(3) * (g - b)
+	if almostEqual(x, 0) && almostEqual(y, 0) {
+		h = 0
+	} else {
+		h = hueAngle(x, y)
+	}
+	return
+}
+
+// HSI -> RGB (inputs: H 0..360, S 0..100, I 0..100) -> RGB 0..255.
+// Часть сочетаний HSI лежит вне куба RGB - значения не обрезаются.
+func HSIToRGB(hDeg, sPct, iPct float64) (r, g, b float64) {
+	h := math.Mod(hDeg, 360)
+	if h < 0 {
+		h += 360
+	}
+	s := clampFloat(sPct/100.0, 0, 1)
+	i := clampFloat(iPct/100.0, 0, 1)
+
+	// в каждом секторе 120° одна компонента равна I*(1-S), вторая считается по формуле, третья - остаток
+	sector := func(hs float64) (x, y, z float64) {
+		x = i * (1 - s)
+		y = i * (1 + s*math.Cos(degToRad(hs))/math.Cos(degToRad(60-hs)))
+		z = 3*i - (x + y)
+		return
+	}
+
+	var rp, gp, bp float64
+	switch {
+	case h < 120:
+		bp, rp, gp = sector(h)
+	case h < 240:
+		rp, gp, bp = sector(h - 120)
+	default:
+		gp, bp, rp = sector(h - 240)
+	}
+
+	r = rp * 255.0
+	g = gp * 255.0
+	b = bp * 255.0
+	return
+}
@@ -0,0 +1,42 @@
+package colors
+
+// matrix3 - матрица 3x3 для линейных преобразований между пространствами
+type matrix3 [3][3]float64
+
+func (m matrix3) apply(a, b, c float64) (float64, float64, float64) {
+	return m[0][0]*a + m[0][1]*b + m[0][2]*c,
+		m[1][0]*a + m[1][1]*b + m[1][2]*c,
+		m[2][0]*a + m[2][1]*b + m[2][2]*c
+}
+
+// inverse - обратная матрица (через алгебраические дополнения).
+// Обратные матрицы считаются из прямых, а не берутся из литературы: опубликованные
+// коэффициенты округлены, и прямое с обратным преобразование давало бы ошибку ~1e-4.
+func (m matrix3) inverse() matrix3 {
+	det := m[0][0]*(m[1][1]*m[2][2]-m[1][2]*m[2][1]) -
+		m[0][1]*(m[1][0]*m[2][2]-m[1][2]*m[2][0]) +
+		m[0][2]*(m[1][0]*m[2][1]-m[1][1]*m[2][0])
+	var inv matrix3
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			// дополнение элемента (j, i), знак учтен циклическим порядком индексов
+			r1, r2 := (j+1)%3, (j+2)%3
+			c1, c2 := (i+1)%3, (i+2)%3
+			inv[i][j] = (m[r1][c1]*m[r2][c2] - m[r1][c2]*m[r2][c1]) / det
+		}
+	}
+	return inv
+}
+
+// mul - произведение матриц m·n (сначала применяется n, затем m)
+func (m matrix3) mul(n matrix3) matrix3 {
+	var p matrix3
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			for k := 0; k < 3; k++ {
+				p[i][j] += m[i][k] * n[k][j]
+			}
+		}
+	}
+	return p
+}
@@ -0,0 +1,193 @@
+package colors
+
+import (
+	"errors"
+	"math"
+)
+
+// ---------- Смешение цветов ----------
+
+// MixMode - модель смешения
+type MixMode int
+
+const (
+	// MixAdditive - сложение света (лучи прожекторов): линейные каналы суммируются
+	// с весами относительно самого яркого источника, красный + зеленый = желтый.
+	// Результат может быть ярче белого.
+	MixAdditive MixMode = iota
+	// MixSubtractive - наложение светофильтров: пропускание 1-CMY перемножается,
+	// веса - показатели степени (взвешенное геометрическое среднее), желтый + голубой = зеленый.
+	MixSubtractive
+	// MixPigment - смешение красок по Кубелке-Мунку: коэффициенты K/S складываются
+	// по длинам волн, синий + желтый дают зеленый, а не серый.
+	MixPigment
+)
+
+// Mix смешивает цвета в долях weights (нормируются к сумме 1; nil - поровну).
+// Результат - линейный sRGB без обрезки.
+func Mix(cs []Color, weights []float64, mode MixMode) (LinearRGB, error) {
+	if len(cs) == 0 {
+		return LinearRGB{}, errors.New("nothing to mix")
+	}
+	w, err := mixWeights(len(cs), weights)
+	if err != nil {
+		return LinearRGB{}, err
+	}
+	lin := make([][3]float64, len(cs))
+	for i, c := range cs {
+		l := ToLinearRGB(c, nil)
+		lin[i] = [3]float64{l.R, l.G, l.B}
+	}
+
+	var out [3]float64
+	switch mode {
+	case MixAdditive:
+		out = mixAdditive(lin, w)
+	case MixSubtractive:
+		out = mixSubtractive(lin, w)
+	case MixPigment:
+		out = mixPigment(lin, w)
+	default:
+		return LinearRGB{}, errors.New("unknown mix mode")
+	}
+	return LinearRGB{R: out[0], G: out[1], B: out[2]}, nil
+}
+
+func mixWeights(n int, weights []float64) ([]float64, error) {
+	if weights == nil {
+		weights = make([]float64, n)
+		for i := range weights {
+			weights[i] = 1
+		}
+	}
+	if len(weights) != n {
+		return nil, errors.New("one weight per color is required")
+	}
+	strongest := 0.0
+	for _, v := range weights {
+		if v < 0 || math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil, errors.New("weights must be finite and non-negative")
+		}
+		strongest = math.Max(strongest, v)
+	}
+	if strongest == 0 {
+		return nil, errors.New("weights must not all be zero")
+	}
+	// сначала делим на наибольший вес: сумма огромных весов (1e308) иначе переполнится до +Inf
+	sum := 0.0
+	for _, v := range weights {
+		sum += v / strongest
+	}
+	w := make([]float64, n)
+	for i, v := range weights {
+		w[i] = v / strongest / sum
+	}
+	return w, nil
+}
+
+func mixAdditive(lin [][3]float64, w []float64) [3]float64 {
+	strongest := 0.0
+	for _, v := range w {
+		strongest = math.Max(strongest, v)
+	}
+	var out [3]float64
+	for i, c := range lin {
+		for k := range out {
+			out[k] += c[k] * w[i] / strongest
+		}
+	}
+	return out
+}
+
+func mixSubtractive(lin [][3]float64, w []float64) [3]float64 {
+	out := [3]float64{1, 1, 1}
+	for i, c := range lin {
+		for k := range out {
+			out[k] *= math.Pow(clampUnit(c[k]), w[i])
+		}
+	}
+	return out
+}
+
+func clampUnit(v float64) float64 { return math.Min(math.Max(v, 0), 1) }
+
+// ---------- Краски (Кубелка-Мунк) ----------
+//
+// По трем каналам формула Кубелки-Мунка дает почти черную смесь синего и желтого,
+// поэтому каждый цвет сначала превращается в гладкий спектр отражения:
+//
+//	R(λ) = f + (1-f)·(r·Br(λ) + g·Bg(λ) + b·Bb(λ)),  Br + Bg + Bb = 1
+//
+// (базисные кривые перекрываются, как у настоящих пигментов, f - отражение самой темной краски).
+// Поглощение K и рассеяние S смеси - средние по долям; рассеяние краски тем больше, чем она
+// светлее (белила кроют сильнее красного), K/S = (1-R)²/2R. Спектр смеси интегрируется обратно
+// в sRGB. Разница между цветом и его спектром (остаток) смешивается линейно и добавляется
+// к результату, так что смесь цвета с самим собой дает тот же цвет.
+
+const (
+	pigmentBlueGreen   = 495.0 // границы базисных кривых, нм
+	pigmentGreenRed    = 590.0
+	pigmentSoftness    = 12.0 // ширина перехода, нм
+	pigmentFloor       = 0.03
+	pigmentScatterBase = 0.05 // рассеяние черной краски; у белой 1 + pigmentScatterBase
+)
+
+// pigmentBasis - значения Br, Bg, Bb на сетке таблиц CIE
+var pigmentBasis = func() (basis [spectralCount][3]float64) {
+	sigmoid := func(x float64) float64 { return 1 / (1 + math.Exp(-x)) }
+	for i := range basis {
+		nm := SpectralStart + float64(i)*SpectralStep
+		red := sigmoid((nm - pigmentGreenRed) / pigmentSoftness)
+		notBlue := sigmoid((nm - pigmentBlueGreen) / pigmentSoftness)
+		basis[i] = [3]float64{red, notBlue - red, 1 - notBlue}
+	}
+	return
+}()
+
+// pigmentSpectrum - спектр отражения для линейного цвета (каналы обрезаются до 0..1)
+func pigmentSpectrum(c [3]float64) (s [spectralCount]float64) {
+	for i, b := range pigmentBasis {
+		s[i] = pigmentFloor + (1-pigmentFloor)*(c[0]*b[0]+c[1]*b[1]+c[2]*b[2])
+	}
+	return
+}
+
+// spectrumLinearRGB - линейный sRGB спектра отражения под D65
+func spectrumLinearRGB(s *[spectralCount]float64) [3]float64 {
+	xyz := integrateSpectrum(func(nm float64) float64 {
+		return s[int(math.Round((nm-SpectralStart)/SpectralStep))]
+	}, IlluminantD65, Observer2)
+	r, g, b := SRGB.linearFromRGB(xyz.RGB())
+	return [3]float64{r, g, b}
+}
+
+func mixPigment(lin [][3]float64, w []float64) [3]float64 {
+	var absorb, scatter [spectralCount]float64
+	var residual [3]float64
+	for i, c := range lin {
+		for ch := range c {
+			c[ch] = clampUnit(c[ch])
+		}
+		s := pigmentSpectrum(c)
+		sc := pigmentScatterBase + 0.2126*c[0] + 0.7152*c[1] + 0.0722*c[2]
+		for j, r := range s {
+			absorb[j] += w[i] * sc * (1 - r) * (1 - r) / (2 * r)
+			scatter[j] += w[i] * sc
+		}
+		back := spectrumLinearRGB(&s)
+		for ch := range residual {
+			residual[ch] += w[i] * (c[ch] - back[ch])
+		}
+	}
+
+	var mixed [spectralCount]float64
+	for j := range mixed {
+		ks := absorb[j] / scatter[j]
+		mixed[j] = 1 + ks - math.Sqrt(ks*ks+2*ks)
+	}
+	out := spectrumLinearRGB(&mixed)
+	for ch := range out {
+		out[ch] = clampUnit(out[ch] + residual[ch])
+	}
+	return out
+}
@@ -0,0 +1,70 @@
+package colors
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMix(t *testing.T) {
+	red, yellow, blue := RGB{R: 255}, RGB{R: 255, G: 255}, RGB{B: 255}
+	white, black := RGB{R: 255, G: 255, B: 255}, RGB{}
+	mix := func(mode MixMode, weights []float64, cs ...Color) RGB {
+		t.Helper()
+		l, err := Mix(cs, weights, mode)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ToRGB(l)
+	}
+	near := func(a, b RGB) bool { return rgbError(a, b) < 0.5 }
+
+	// свет: красный + зеленый = желтый, все три = белый, одна лампа вдвое слабее
+	if got := mix(MixAdditive, nil, red, RGB{G: 255}); !near(got, yellow) {
+		t.Errorf("additive red+green = %v", got)
+	}
+	if got := mix(MixAdditive, nil, red, RGB{G: 255}, blue); !near(got, white) {
+		t.Errorf("additive r+g+b = %v", got)
+	}
+	if l, _ := Mix([]Color{red, blue}, []float64{2, 1}, MixAdditive); math.Abs(l.R-1) > 1e-12 || math.Abs(l.B-0.5) > 1e-12 {
+		t.Errorf("additive 2:1 = %+v", l)
+	}
+
+	// фильтры: желтый + голубой = зеленый, прозрачный фильтр цвет не меняет
+	if got := mix(MixSubtractive, nil, yellow, RGB{G: 255, B: 255}); !near(got, RGB{G: 255}) {
+		t.Errorf("subtractive yellow+cyan = %v", got)
+	}
+	if got := mix(MixSubtractive, []float64{1, 3}, red, white); !near(got, red) {
+		t.Errorf("subtractive red+white = %v", got)
+	}
+
+	// краски: синий + желтый - зеленый, красный + синий - пурпурный, черный + белый - серый
+	if got := mix(MixPigment, nil, blue, yellow); got.G < got.R+50 || got.G < got.B+50 {
+		t.Errorf("pigment blue+yellow = %v, want green", got)
+	}
+	if got := mix(MixPigment, nil, red, blue); got.R < got.G+50 || got.B < got.G+20 {
+		t.Errorf("pigment red+blue = %v, want purple", got)
+	}
+	if got := mix(MixPigment, nil, black, white); math.Abs(got.R-got.G) > 1 || math.Abs(got.G-got.B) > 1 || got.G < 50 || got.G > 220 {
+		t.Errorf("pigment black+white = %v, want gray", got)
+	}
+
+	// смесь фильтров или красок с такими же не меняет цвет (свет при этом становится ярче)
+	for _, mode := range []MixMode{MixSubtractive, MixPigment} {
+		forEachRGB(5, func(c RGB) {
+			if got := mix(mode, []float64{0.3, 0.7}, c, c); rgbError(c, got) > 1e-6 {
+				t.Fatalf("mode %d: %v mixed with itself = %v", mode, c, got)
+			}
+		})
+	}
+
+	// огромные веса нормируются без переполнения
+	if l, err := Mix([]Color{red, blue}, []float64{1e308, 1e308}, MixAdditive); err != nil || math.Abs(l.R-1) > 1e-12 || math.Abs(l.B-1) > 1e-12 {
+		t.Errorf("additive weights 1e308: %+v, %v", l, err)
+	}
+
+	for _, w := range [][]float64{{1}, {-1, 2}, {0, 0}, {math.NaN(), 1}} {
+		if _, err := Mix([]Color{red, blue}, w, MixPigment); err == nil {
+			t.Errorf("weights %v: expected error", w)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package colors
+
+import "math"
+
+// ---------- OKLab (Björn Ottosson, 2020) ----------
+
+var (
+	// линейный sRGB -> LMS
+	srgbToLMSMatrix = matrix3{
+		{0.4122214708, 0.5363325363, 0.0514459929},
+		{0.2119034982, 0.6806995451, 0.1073969566},
+		{0.0883024619, 0.2817188376, 0.6299787005},
+	}
+	// LMS' (после кубического корня) -> OKLab
+	lmsToOKLabMatrix = matrix3{
+		{0.2104542553, 0.7936177850, -0.0040720468},
+		{1.9779984951, -2.4285922050, 0.4505937099},
+		{0.0259040371, 0.7827717662, -0.8086757660},
+	}
+	lmsToSRGBMatrix  = srgbToLMSMatrix.inverse()
+	okLabToLMSMatrix = lmsToOKLabMatrix.inverse()
+)
+
+// RGB 0..255 -> OKLab: L 0..1, a/b примерно -0.4..0.4
+func RGBToOKLab(rInt, gInt, bInt int) (l, a, b float64) {
+	return rgbToOKLab(
+		clampFloat(float64(rInt), 0, 255),
+		clampFloat(float64(gInt), 0, 255),
+		clampFloat(float64(bInt), 0, 255),
+	)
+}
+
+// RGB (дробные 0..255, без обрезки) -> OKLab
+func rgbToOKLab(r255, g255, b255 float64) (l, a, b float64) {
+	r := SRGBToLinear(r255 / 255.0)
+	g := SRGBToLinear(g255 / 255.0)
+	bl := SRGBToLinear(b255 / 255.0)
+
+	lc, mc, sc := srgbToLMSMatrix.apply(r, g, bl)
+	return lmsToOKLabMatrix.apply(math.Cbrt(lc), math.Cbrt(mc), math.Cbrt(sc))
+}
+
+// OKLab -> RGB 0..255 (без обрезки)
+func OKLabToRGB(l, a, b float64) (r, g, bl float64) {
+	lc, mc, sc := okLabToLMSMatrix.apply(l, a, b)
+	lc, mc, sc = lc*lc*lc, mc*mc*mc, sc*sc*sc
+
+	lr, lg, lb := lmsToSRGBMatrix.apply(lc, mc, sc)
+	return LinearToSRGB(lr) * 255.0, LinearToSRGB(lg) * 255.0, LinearToSRGB(lb) * 255.0
+}
+
+// ---------- OKLCh ----------
+
+// OKLab -> OKLCh: C - насыщенность (примерно 0..0.4), H - тон 0..360
+func OKLabToOKLCh(l, a, b float64) (L, c, h float64) {
+	return labToLCh(l, a, b)
+}
+
+// OKLCh -> OKLab
+func OKLChToOKLab(l, c, h float64) (L, a, b float64) {
+	return lchToLab(l, c, h)
+}
+
+// OKLCh -> RGB 0..255 (без обрезки)
+func OKLChToRGB(l, c, h float64) (r, g, b float64) {
+	return OKLabToRGB(OKLChToOKLab(l, c, h))
+}
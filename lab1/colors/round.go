@@ -0,0 +1,113 @@
+package colors
+
+import "math"
+
+// ---------- Округление для вывода ----------
+//
+// Rounded округляет значения до точности, с которой их показывает сервер lab1.
+// При такой точности любое 8-битное RGB переводится в модель и обратно
+// с ошибкой не больше 0.5 по каждому каналу, т.е. восстанавливается без потерь
+// (см. таблицу погрешностей в Readme.md).
+
+func (c RGB) Rounded() RGB {
+	return RGB{R: math.Round(c.R), G: math.Round(c.G), B: math.Round(c.B)}
+}
+
+// Rounded - проценты с 2 знаками
+func (c CMYK) Rounded() CMYK {
+	return CMYK{C: round(c.C, 2), M: round(c.M, 2), Y: round(c.Y, 2), K: round(c.K, 2)}
+}
+
+// Rounded - 2 знака
+func (c HSV) Rounded() HSV {
+	return HSV{H: round(c.H, 2), S: round(c.S, 2), V: round(c.V, 2)}
+}
+
+// Rounded - 2 знака
+func (c HSL) Rounded() HSL {
+	return HSL{H: round(c.H, 2), S: round(c.S, 2), L: round(c.L, 2)}
+}
+
+// Rounded - 2 знака
+func (c HWB) Rounded() HWB {
+	return HWB{H: round(c.H, 2), W: round(c.W, 2), B: round(c.B, 2)}
+}
+
+// Rounded - 2 знака
+func (c HSI) Rounded() HSI {
+	return HSI{H: round(c.H, 2), S: round(c.S, 2), I: round(c.I, 2)}
+}
+
+// Rounded - 3 знака
+func (c XYZ) Rounded() XYZ {
+	return XYZ{X: round(c.X, 3), Y: round(c.Y, 3), Z: round(c.Z, 3)}
+}
+
+// Rounded - 2 знака
+func (c Lab) Rounded() Lab {
+	return Lab{L: round(c.L, 2), A: round(c.A, 2), B: round(c.B, 2)}
+}
+
+// Rounded - 2 знака
+func (c LCh) Rounded() LCh {
+	return LCh{L: round(c.L, 2), C: round(c.C, 2), H: round(c.H, 2)}
+}
+
+// Rounded - 5 знаков
+func (c OKLab) Rounded() OKLab {
+	return OKLab{L: round(c.L, 5), A: round(c.A, 5), B: round(c.B, 5)}
+}
+
+// Rounded - L и C с 5 знаками, H с 3
+func (c OKLCh) Rounded() OKLCh {
+	return OKLCh{L: round(c.L, 5), C: round(c.C, 5), H: round(c.H, 3)}
+}
+
+// Rounded - K с 1 знаком, Duv с 5 (цвет задается только цветностью, на RGB это не влияет)
+func (c Kelvin) Rounded() Kelvin {
+	return Kelvin{K: round(c.K, 1), Duv: round(c.Duv, 5)}
+}
+
+// Rounded - 2 знака (кодовые значения 0..255)
+func (c YCbCr) Rounded() YCbCr {
+	return YCbCr{Y: round(c.Y, 2), Cb: round(c.Cb, 2), Cr: round(c.Cr, 2), Standard: c.Standard, Limited: c.Limited}
+}
+
+// Rounded - 4 знака
+func (c YUV) Rounded() YUV {
+	return YUV{Y: round(c.Y, 4), U: round(c.U, 4), V: round(c.V, 4), Standard: c.Standard}
+}
+
+// Rounded - 4 знака
+func (c YIQ) Rounded() YIQ {
+	return YIQ{Y: round(c.Y, 4), I: round(c.I, 4), Q: round(c.Q, 4)}
+}
+
+// Rounded - 2 знака (в отличие от RGB: при переводе обратно в sRGB целых значений другого пространства не хватает)
+func (c SpaceRGB) Rounded() SpaceRGB {
+	return SpaceRGB{R: round(c.R, 2), G: round(c.G, 2), B: round(c.B, 2), Space: c.Space}
+}
+
+// Rounded - 5 знаков
+func (c LinearRGB) Rounded() LinearRGB {
+	return LinearRGB{R: round(c.R, 5), G: round(c.G, 5), B: round(c.B, 5), Space: c.Space}
+}
+
+// Rounded - 5 знаков
+func (c PQ) Rounded() PQ {
+	return PQ{R: round(c.R, 5), G: round(c.G, 5), B: round(c.B, 5), Space: c.Space}
+}
+
+// Rounded - 5 знаков
+func (c HLG) Rounded() HLG {
+	return HLG{R: round(c.R, 5), G: round(c.G, 5), B: round(c.B, 5), Space: c.Space}
+}
+
+func round(x float64, prec int) float64 {
+	p := math.Pow(10, float64(prec))
+	r := math.Round(x*p) / p
+	if r == 0 {
+		return 0 // без "-0" в JSON
+	}
+	return r
+}
@@ -0,0 +1,144 @@
+package colors
+
+import "math"
+
+// ---------- Рабочие RGB-пространства ----------
+
+// RGBSpace - RGB-пространство с белой точкой D65: первичные цвета и передаточная функция.
+// Переводы между пространствами идут через XYZ в линейных каналах.
+type RGBSpace struct {
+	Name string
+
+	toXYZ, fromXYZ matrix3                 // линейные каналы 0..1 <-> XYZ (Y 0..1)
+	decode         func(v float64) float64 // кодированное значение 0..1 -> линейное
+	encode         func(v float64) float64 // линейное -> кодированное
+}
+
+var (
+	SRGB = &RGBSpace{Name: "srgb", toXYZ: srgbToXYZMatrix, fromXYZ: xyzToSRGBMatrix,
+		decode: SRGBToLinear, encode: LinearToSRGB}
+
+	// Display P3: первичные DCI-P3, белая точка D65, передаточная функция sRGB
+	DisplayP3 = newRGBSpace("display-p3", [3][2]float64{{0.680, 0.320}, {0.265, 0.690}, {0.150, 0.060}},
+		SRGBToLinear, LinearToSRGB)
+
+	// Adobe RGB (1998): гамма 563/256 ≈ 2.2
+	AdobeRGB = newRGBSpace("adobe-rgb", [3][2]float64{{0.640, 0.330}, {0.210, 0.710}, {0.150, 0.060}},
+		gammaDecode(563.0/256), gammaEncode(563.0/256))
+
+	// ITU-R BT.2020: передаточная функция BT.2020 (как и у CSS rec2020)
+	Rec2020 = newRGBSpace("rec2020", [3][2]float64{{0.708, 0.292}, {0.170, 0.797}, {0.131, 0.046}},
+		rec2020Decode, rec2020Encode)
+)
+
+// RGBSpaces - все пространства пакета, от меньшего охвата к большему
+var RGBSpaces = []*RGBSpace{SRGB, DisplayP3, AdobeRGB, Rec2020}
+
+// newRGBSpace строит матрицу RGB -> XYZ по координатам цветности первичных цветов (x, y).
+// Белая точка - та же D65, что у sRGB, поэтому белый (255, 255, 255) во всех пространствах один и тот же.
+func newRGBSpace(name string, primaries [3][2]float64, decode, encode func(float64) float64) *RGBSpace {
+	// столбцы - XYZ первичных цветов при Y = 1
+	var p matrix3
+	for i, xy := range primaries {
+		p[0][i] = xy[0] / xy[1]
+		p[1][i] = 1
+		p[2][i] = (1 - xy[0] - xy[1]) / xy[1]
+	}
+	// масштабы первичных цветов, при которых их сумма дает белую точку
+	sr, sg, sb := p.inverse().apply(whiteX/whiteY, 1, whiteZ/whiteY)
+	for i := 0; i < 3; i++ {
+		p[i][0] *= sr
+		p[i][1] *= sg
+		p[i][2] *= sb
+	}
+	return &RGBSpace{Name: name, toXYZ: p, fromXYZ: p.inverse(), decode: decode, encode: encode}
+}
+
+// Степенные передаточные функции; знак сохраняется для значений вне 0..1
+func gammaDecode(gamma float64) func(float64) float64 {
+	return func(v float64) float64 { return math.Copysign(math.Pow(math.Abs(v), gamma), v) }
+}
+
+func gammaEncode(gamma float64) func(float64) float64 {
+	return func(v float64) float64 { return math.Copysign(math.Pow(math.Abs(v), 1/gamma), v) }
+}
+
+const (
+	rec2020Alpha = 1.09929682680944
+	rec2020Beta  = 0.018053968510807
+)
+
+func rec2020Decode(v float64) float64 {
+	a := math.Abs(v)
+	if a < rec2020Beta*4.5 {
+		return v / 4.5
+	}
+	return math.Copysign(math.Pow((a+rec2020Alpha-1)/rec2020Alpha, 1/0.45), v)
+}
+
+func rec2020Encode(v float64) float64 {
+	a := math.Abs(v)
+	if a < rec2020Beta {
+		return v * 4.5
+	}
+	return math.Copysign(rec2020Alpha*math.Pow(a, 0.45)-(rec2020Alpha-1), v)
+}
+
+// linearToRGB - линейные каналы пространства (1 = белый) -> sRGB 0..255
+func (s *RGBSpace) linearToRGB(r, g, b float64) RGB {
+	if s != SRGB {
+		r, g, b = xyzToSRGBMatrix.apply(s.toXYZ.apply(r, g, b))
+	}
+	return RGB{R: LinearToSRGB(r) * 255, G: LinearToSRGB(g) * 255, B: LinearToSRGB(b) * 255}
+}
+
+// linearFromRGB - sRGB 0..255 -> линейные каналы пространства
+func (s *RGBSpace) linearFromRGB(p RGB) (r, g, b float64) {
+	r, g, b = SRGBToLinear(p.R/255), SRGBToLinear(p.G/255), SRGBToLinear(p.B/255)
+	if s != SRGB {
+		r, g, b = s.fromXYZ.apply(srgbToXYZMatrix.apply(r, g, b))
+	}
+	return r, g, b
+}
+
+// SpaceRGB - цвет в рабочем пространстве Space, каналы 0..255 (дробные, без обрезки)
+type SpaceRGB struct {
+	R float64 `json:"r"`
+	G float64 `json:"g"`
+	B float64 `json:"b"`
+
+	Space *RGBSpace `json:"-"`
+}
+
+func (c SpaceRGB) RGB() RGB {
+	if c.Space == SRGB {
+		return RGB{R: c.R, G: c.G, B: c.B}
+	}
+	s := c.Space
+	return s.linearToRGB(s.decode(c.R/255), s.decode(c.G/255), s.decode(c.B/255))
+}
+
+// ToSpaceRGB - цвет в пространстве s (без обрезки). Значение в том же пространстве возвращается как есть.
+func ToSpaceRGB(c Color, s *RGBSpace) SpaceRGB {
+	if v, ok := c.(SpaceRGB); ok && v.Space == s {
+		return v
+	}
+	p := c.RGB()
+	if s == SRGB {
+		return SpaceRGB{R: p.R, G: p.G, B: p.B, Space: s}
+	}
+	r, g, b := s.linearFromRGB(p)
+	return SpaceRGB{R: s.encode(r) * 255, G: s.encode(g) * 255, B: s.encode(b) * 255, Space: s}
+}
+
+// InSpaceGamut проверяет, что цвет лежит внутри куба пространства s (с допуском GamutTolerance)
+func InSpaceGamut(c Color, s *RGBSpace) bool {
+	p := ToSpaceRGB(c, s)
+	return InGamut(RGB{R: p.R, G: p.G, B: p.B})
+}
+
+// ClipToSpace - цвет в пространстве s с каналами, обрезанными до 0..255
+func ClipToSpace(c Color, s *RGBSpace) SpaceRGB {
+	p := ToSpaceRGB(c, s)
+	return SpaceRGB{R: clampFloat(p.R, 0, 255), G: clampFloat(p.G, 0, 255), B: clampFloat(p.B, 0, 255), Space: s}
+}
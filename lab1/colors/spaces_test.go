@@ -0,0 +1,65 @@
+package colors
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSpaceRoundTrip(t *testing.T) {
+	for _, s := range RGBSpaces {
+		worstFloat, worstRounded := 0.0, 0.0
+		forEachRGB(5, func(c RGB) {
+			worstFloat = math.Max(worstFloat, rgbError(c, ToSpaceRGB(c, s).RGB()))
+			worstRounded = math.Max(worstRounded, rgbError(c, ToSpaceRGB(c, s).Rounded().RGB()))
+		})
+		if worstFloat > floatBound {
+			t.Errorf("%s: float error %g, want <= %g", s.Name, worstFloat, floatBound)
+		}
+		if worstRounded >= 0.5 {
+			t.Errorf("%s: rounded error %.4f, want < 0.5", s.Name, worstRounded)
+		}
+	}
+}
+
+func TestSpaceKnownValues(t *testing.T) {
+	approx := func(a, b float64) bool { return math.Abs(a-b) <= 0.2 }
+	red, white := RGB{R: 255}, RGB{R: 255, G: 255, B: 255}
+
+	// значения CSS Color 4 (color(display-p3 0.9175 0.2003 0.1387) и т.д.), умноженные на 255
+	cases := []struct {
+		space *RGBSpace
+		in    RGB
+		want  RGB
+	}{
+		{DisplayP3, red, RGB{R: 233.96, G: 51.08, B: 35.37}},
+		{AdobeRGB, red, RGB{R: 218.97, G: 0, B: 0}},
+		{Rec2020, red, RGB{R: 201.96, G: 58.91, B: 18.82}},
+		{DisplayP3, white, white},
+		{AdobeRGB, white, white},
+		{Rec2020, white, white},
+	}
+	for _, c := range cases {
+		got := ToSpaceRGB(c.in, c.space)
+		if !approx(got.R, c.want.R) || !approx(got.G, c.want.G) || !approx(got.B, c.want.B) {
+			t.Errorf("%s %v = %+v, want %v", c.space.Name, c.in, got, c.want)
+		}
+	}
+}
+
+func TestSpaceGamut(t *testing.T) {
+	p3Green := SpaceRGB{G: 255, Space: DisplayP3}
+	if InGamut(p3Green) {
+		t.Error("display-p3 green is inside sRGB")
+	}
+	if !InSpaceGamut(p3Green, DisplayP3) || !InSpaceGamut(p3Green, Rec2020) {
+		t.Error("display-p3 green is outside display-p3 or rec2020")
+	}
+	for _, s := range RGBSpaces {
+		if !InSpaceGamut(RGB{R: 255, G: 128}, s) {
+			t.Errorf("sRGB orange is outside %s", s.Name)
+		}
+	}
+	if c := ClipToSpace(SpaceRGB{R: 300, G: -4, B: 10, Space: Rec2020}, Rec2020); c.R != 255 || c.G != 0 || c.B != 10 {
+		t.Errorf("clipped = %+v", c)
+	}
+}
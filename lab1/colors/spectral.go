@@ -0,0 +1,187 @@
+package colors
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ---------- Спектральные данные -> XYZ ----------
+//
+// XYZ образца считается суммированием по сетке 380..780 нм с шагом 5 нм (CIE 15:2004):
+//
+//	X = k·Σ S(λ)·R(λ)·x̄(λ),  k = 100 / Σ S(λ)·ȳ(λ)
+//
+// где S - источник, R - коэффициент отражения образца, x̄ȳz̄ - функции сложения наблюдателя.
+// Идеальный белый (R = 1) дает белую точку источника с Y = 100.
+
+// Сетка таблиц CIE
+const (
+	SpectralStart = 380.0
+	SpectralEnd   = 780.0
+	SpectralStep  = 5.0
+	spectralCount = 81
+)
+
+// Observer - стандартный колориметрический наблюдатель
+type Observer int
+
+const (
+	Observer2  Observer = iota // CIE 1931, поле зрения 2°
+	Observer10                 // CIE 1964, поле зрения 10°
+)
+
+// Illuminant - стандартный источник света
+type Illuminant int
+
+const (
+	IlluminantD65 Illuminant = iota // дневной свет 6504 K
+	IlluminantD50                   // дневной свет 5003 K (полиграфия)
+	IlluminantA                     // лампа накаливания 2856 K
+	IlluminantE                     // равноэнергетический
+)
+
+//go:embed cie/cmf1931.csv
+var cmf1931CSV string
+
+//go:embed cie/cmf1964.csv
+var cmf1964CSV string
+
+//go:embed cie/illuminants.csv
+var illuminantsCSV string
+
+var (
+	cmf1931     = mustParseCIETable(cmf1931CSV, 3)
+	cmf1964     = mustParseCIETable(cmf1964CSV, 3)
+	illuminants = mustParseCIETable(illuminantsCSV, 2)
+)
+
+// mustParseCIETable разбирает встроенную таблицу "nm,c1,c2,..." на сетке 380..780 нм
+func mustParseCIETable(data string, columns int) [spectralCount][]float64 {
+	r := csv.NewReader(strings.NewReader(data))
+	r.Comment = '#'
+	records, err := r.ReadAll()
+	if err != nil {
+		panic(err)
+	}
+	var table [spectralCount][]float64
+	if len(records) != spectralCount+1 {
+		panic(fmt.Sprintf("cie table: %d rows, want %d", len(records)-1, spectralCount))
+	}
+	for i, rec := range records[1:] { // первая строка - заголовок
+		if len(rec) != columns+1 {
+			panic(fmt.Sprintf("cie table: row %d has %d columns", i, len(rec)))
+		}
+		if nm, _ := strconv.ParseFloat(rec[0], 64); nm != SpectralStart+float64(i)*SpectralStep {
+			panic(fmt.Sprintf("cie table: row %d is %s nm", i, rec[0]))
+		}
+		table[i] = make([]float64, columns)
+		for j := range table[i] {
+			if table[i][j], err = strconv.ParseFloat(rec[j+1], 64); err != nil {
+				panic(err)
+			}
+		}
+	}
+	return table
+}
+
+// illuminantPower - относительная мощность источника на i-м шаге сетки
+func illuminantPower(ill Illuminant, i int) float64 {
+	switch ill {
+	case IlluminantD50:
+		return illuminants[i][1]
+	case IlluminantA:
+		// определение CIE через формулу Планка, нормировка 100 на 560 нм
+		const c2 = 1.435e7 // нм·K
+		nm := SpectralStart + float64(i)*SpectralStep
+		return 100 * math.Pow(560/nm, 5) * math.Expm1(c2/(2848*560)) / math.Expm1(c2/(2848*nm))
+	case IlluminantE:
+		return 100
+	}
+	return illuminants[i][0]
+}
+
+func observerTable(obs Observer) *[spectralCount][]float64 {
+	if obs == Observer10 {
+		return &cmf1964
+	}
+	return &cmf1931
+}
+
+// Spectrum - измеренная кривая: коэффициент отражения (обычно 0..1) на длинах волн Wavelengths (нм)
+type Spectrum struct {
+	Wavelengths []float64
+	Values      []float64
+}
+
+// Validate проверяет, что длины волн возрастают и каждой соответствует значение
+func (s Spectrum) Validate() error {
+	if len(s.Wavelengths) != len(s.Values) {
+		return fmt.Errorf("%d wavelengths but %d values", len(s.Wavelengths), len(s.Values))
+	}
+	if len(s.Wavelengths) < 2 {
+		return errors.New("spectrum needs at least 2 points")
+	}
+	for i := 1; i < len(s.Wavelengths); i++ {
+		if s.Wavelengths[i] <= s.Wavelengths[i-1] {
+			return fmt.Errorf("wavelengths must be strictly increasing (%g after %g)", s.Wavelengths[i], s.Wavelengths[i-1])
+		}
+	}
+	for _, v := range s.Values {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return errors.New("spectrum values must be finite")
+		}
+	}
+	return nil
+}
+
+// At - значение на длине волны nm: линейная интерполяция между измерениями,
+// за пределами измеренного диапазона - ближайшее крайнее значение (рекомендация CIE 15)
+func (s Spectrum) At(nm float64) float64 {
+	w := s.Wavelengths
+	i := sort.SearchFloat64s(w, nm)
+	switch {
+	case i == 0:
+		return s.Values[0]
+	case i == len(w):
+		return s.Values[len(w)-1]
+	case w[i] == nm:
+		return s.Values[i]
+	}
+	f := (nm - w[i-1]) / (w[i] - w[i-1])
+	return s.Values[i-1] + (s.Values[i]-s.Values[i-1])*f
+}
+
+// SpectrumToXYZ - XYZ образца под источником ill для наблюдателя obs (Y белого = 100).
+// Результат относительно белой точки IlluminantWhite(ill, obs), а не D65.
+func SpectrumToXYZ(s Spectrum, ill Illuminant, obs Observer) (XYZ, error) {
+	if err := s.Validate(); err != nil {
+		return XYZ{}, err
+	}
+	return integrateSpectrum(s.At, ill, obs), nil
+}
+
+// IlluminantWhite - белая точка источника для наблюдателя (идеальный белый, R = 1)
+func IlluminantWhite(ill Illuminant, obs Observer) XYZ {
+	return integrateSpectrum(func(float64) float64 { return 1 }, ill, obs)
+}
+
+func integrateSpectrum(reflectance func(nm float64) float64, ill Illuminant, obs Observer) XYZ {
+	cmf := observerTable(obs)
+	var x, y, z, norm float64
+	for i := 0; i < spectralCount; i++ {
+		p := illuminantPower(ill, i)
+		r := reflectance(SpectralStart + float64(i)*SpectralStep)
+		x += p * r * cmf[i][0]
+		y += p * r * cmf[i][1]
+		z += p * r * cmf[i][2]
+		norm += p * cmf[i][1]
+	}
+	k := 100 / norm
+	return XYZ{X: k * x, Y: k * y, Z: k * z}
+}
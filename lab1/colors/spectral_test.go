@@ -0,0 +1,67 @@
+package colors
+
+import (
+	"math"
+	"testing"
+)
+
+// Белые точки источников, проинтегрированные по встроенным таблицам, совпадают с опубликованными
+func TestIlluminantWhite(t *testing.T) {
+	cases := []struct {
+		ill  Illuminant
+		obs  Observer
+		want XYZ
+	}{
+		{IlluminantD65, Observer2, XYZ{X: 95.047, Y: 100, Z: 108.883}},
+		{IlluminantD50, Observer2, XYZ{X: 96.422, Y: 100, Z: 82.521}},
+		{IlluminantA, Observer2, XYZ{X: 109.850, Y: 100, Z: 35.585}},
+		{IlluminantD65, Observer10, XYZ{X: 94.811, Y: 100, Z: 107.304}},
+		{IlluminantD50, Observer10, XYZ{X: 96.720, Y: 100, Z: 81.427}},
+		{IlluminantE, Observer2, XYZ{X: 100, Y: 100, Z: 100}},
+	}
+	for _, c := range cases {
+		if got := IlluminantWhite(c.ill, c.obs); xyzDist(got, c.want) > 0.03 {
+			t.Errorf("illuminant %d, observer %d: white %v, want %v", c.ill, c.obs, got, c.want)
+		}
+	}
+}
+
+func TestSpectrumToXYZ(t *testing.T) {
+	// ровный серый 50% на 380..730 нм (как у спектрофотометра): Y = 50, цветность белой точки
+	var s Spectrum
+	for nm := 380.0; nm <= 730; nm += 10 {
+		s.Wavelengths = append(s.Wavelengths, nm)
+		s.Values = append(s.Values, 0.5)
+	}
+	xyz, err := SpectrumToXYZ(s, IlluminantD65, Observer2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (XYZ{X: 95.047 / 2, Y: 50, Z: 108.883 / 2}); xyzDist(xyz, want) > 0.02 {
+		t.Errorf("gray = %v, want %v", xyz, want)
+	}
+
+	// линейная интерполяция и продление крайних значений
+	ramp := Spectrum{Wavelengths: []float64{400, 700}, Values: []float64{0, 1}}
+	for nm, want := range map[float64]float64{380: 0, 550: 0.5, 700: 1, 780: 1} {
+		if got := ramp.At(nm); math.Abs(got-want) > 1e-12 {
+			t.Errorf("At(%g) = %v, want %v", nm, got, want)
+		}
+	}
+	// красноватый образец: больше отражает в длинных волнах
+	xyz, _ = SpectrumToXYZ(ramp, IlluminantD65, Observer2)
+	if rgb := ToRGB(xyz); rgb.R <= rgb.B {
+		t.Errorf("ramp rgb = %v, want red > blue", rgb)
+	}
+
+	for _, bad := range []Spectrum{
+		{Wavelengths: []float64{400, 500}, Values: []float64{1}},
+		{Wavelengths: []float64{500, 400}, Values: []float64{1, 1}},
+		{Wavelengths: []float64{400}, Values: []float64{1}},
+		{Wavelengths: []float64{400, 500}, Values: []float64{1, math.NaN()}},
+	} {
+		if _, err := SpectrumToXYZ(bad, IlluminantD65, Observer2); err == nil {
+			t.Errorf("%v: expected error", bad)
+		}
+	}
+}
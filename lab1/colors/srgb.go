@@ -0,0 +1,151 @@
+package colors
+
+import "math"
+
+// ---------- CMYK и HSV ----------
+
+// CMYK (0..100%) -> RGB (0..255)
+func CMYKToRGB(cPct, mPct, yPct, kPct float64) (r, g, b float64) {
+	c := clampFloat(cPct/100.0, 0, 1)
+	m := clampFloat(mPct/100.0, 0, 1)
+	y := clampFloat(yPct/100.0, 0, 1)
+	k := clampFloat(kPct/100.0, 0, 1)
+
+	// стандартый подход: r = 255*(1-c)*(1-k)
+	r = (1.0 - c) * (1.0 - k) * 255.0
+	g = (1.0 - m) * (1.0 - k) * 255.0
+	b = (1.0 - y) * (1.0 - k) * 255.0
+	return
+}
+
+// RGB 0..255 -> CMYK 0..1
+func RGBToCMYK(rInt, gInt, bInt int) (c, m, y, k float64) {
+	return rgbToCMYK(float64(rInt), float64(gInt), float64(bInt))
+}
+
+// RGB (дробные 0..255) -> CMYK 0..1
+func rgbToCMYK(r255, g255, b255 float64) (c, m, y, k float64) {
+	r := clampFloat(r255/255.0, 0, 1)
+	g := clampFloat(g255/255.0, 0, 1)
+	b := clampFloat(b255/255.0, 0, 1)
+
+	k = 1 - math.Max(r, math.Max(g, b))
+	if almostEqual(k, 1.0) {
+		// черный
+		return 0, 0, 0, 1
+	}
+	c = (1 - r - k) / (1 - k)
+	m = (1 - g - k) / (1 - k)
+	y = (1 - b - k) / (1 - k)
+	// защита от негативных нулей
+	c = clampFloat(c, 0, 1)
+	m = clampFloat(m, 0, 1)
+	y = clampFloat(y, 0, 1)
+	return
+}
+
+// RGB 0..255 -> HSV: H 0..360, S 0..1, V 0..1
+func RGBToHSV(rInt, gInt, bInt int) (h, s, v float64) {
+	return rgbToHSV(float64(rInt), float64(gInt), float64(bInt))
+}
+
+// RGB (дробные 0..255) -> HSV: H 0..360, S 0..1, V 0..1
+func rgbToHSV(r255, g255, b255 float64) (h, s, v float64) {
+	r := clampFloat(r255/255.0, 0, 1)
+	g := clampFloat(g255/255.0, 0, 1)
+	b := clampFloat(b255/255.0, 0, 1)
+
+	max := math.Max(r, math.Max(g, b))
+	min := math.Min(r, math.Min(g, b))
+	delta := max - min
+
+	// Hue
+	if almostEqual(delta, 0) {
+		h = 0
+	} else {
+		switch {
+		case almostEqual(max, r):
+			h = 60 * math.Mod((g-b)/delta, 6)
+		case almostEqual(max, g):
+			h = 60 * (((b - r) / delta) + 2)
+		default: // max == b
+			h = 60 * (((r - g) / delta) + 4)
+		}
+		if h < 0 {
+			h += 360
+		}
+	}
+
+	// Saturation
+	if almostEqual(max, 0) {
+		s = 0
+	} else {
+		s = delta / max
+	}
+
+	v = max
+	return
+}
+
+// HSV -> RGB (inputs: H 0..360, S 0..100, V 0..100) -> RGB 0..255
+func HSVToRGB(hDeg, sPct, vPct float64) (r, g, b float64) {
+	h := math.Mod(hDeg, 360)
+	if h < 0 {
+		h += 360
+	}
+	s := clampFloat(sPct/100.0, 0, 1)
+	v := clampFloat(vPct/100.0, 0, 1)
+
+	c := v * s
+	x := c * (1 - math.Abs(math.Mod(h/60.0, 2)-1))
+	m := v - c
+
+	var rp, gp, bp float64
+	switch {
+	case 0 <= h && h < 60:
+		rp, gp, bp = c, x, 0
+	case 60 <= h && h < 120:
+		rp, gp, bp = x, c, 0
+	case 120 <= h && h < 180:
+		rp, gp, bp = 0, c, x
+	case 180 <= h && h < 240:
+		rp, gp, bp = 0, x, c
+	case 240 <= h && h < 300:
+		rp, gp, bp = x, 0, c
+	default:
+		rp, gp, bp = c, 0, x
+	}
+
+	r = (rp + m) * 255.0
+	g = (gp + m) * 255.0
+	b = (bp + m) * 255.0
+	return
+}
+
+// ---------- Вспомогательные ----------
+
+func clampFloat(v, low, high float64) float64 {
+	if math.IsNaN(v) {
+		return low
+	}
+	if v < low {
+		return low
+	}
+	if v > high {
+		return high
+	}
+	return v
+}
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+// WrapHue приводит угол тона к диапазону 0..360
+func WrapHue(h float64) float64 {
+	h = math.Mod(h, 360)
+	if h < 0 {
+		h += 360
+	}
+	return h
+}
@@ -0,0 +1,159 @@
+package colors
+
+// ---------- Видеомодели: YCbCr, YUV, YIQ ----------
+//
+// Все три модели - линейные преобразования гамма-кодированных каналов R'G'B'.
+// Каналы sRGB используются как есть, без пересчета первичных цветов стандарта
+// (у BT.601 и BT.709 они почти совпадают с sRGB, у BT.2020 охват шире).
+
+// VideoStandard - набор коэффициентов яркости Kr, Kb
+type VideoStandard int
+
+const (
+	BT601  VideoStandard = iota // SDTV: Kr = 0.299, Kb = 0.114
+	BT709                       // HDTV: Kr = 0.2126, Kb = 0.0722
+	BT2020                      // UHDTV: Kr = 0.2627, Kb = 0.0593
+)
+
+// lumaCoefficients - Kr и Kb стандарта (Kg = 1 - Kr - Kb)
+func lumaCoefficients(s VideoStandard) (kr, kb float64) {
+	switch s {
+	case BT709:
+		return 0.2126, 0.0722
+	case BT2020:
+		return 0.2627, 0.0593
+	}
+	return 0.299, 0.114
+}
+
+// YCbCr - цифровое YCbCr в 8-битных кодовых значениях.
+// Полный диапазон (как в JPEG): Y 0..255, Cb/Cr 0..255 со смещением 128.
+// Узкий диапазон (Limited): Y 16..235, Cb/Cr 16..240.
+type YCbCr struct {
+	Y  float64 `json:"y"`
+	Cb float64 `json:"cb"`
+	Cr float64 `json:"cr"`
+
+	Standard VideoStandard `json:"-"`
+	Limited  bool          `json:"-"`
+}
+
+// YUV - аналоговое YUV: Y 0..1, U -0.436..0.436, V -0.615..0.615
+type YUV struct {
+	Y float64 `json:"y"`
+	U float64 `json:"u"`
+	V float64 `json:"v"`
+
+	Standard VideoStandard `json:"-"`
+}
+
+// YIQ - NTSC YIQ (коэффициенты FCC): Y 0..1, I -0.596..0.596, Q -0.523..0.523
+type YIQ struct {
+	Y float64 `json:"y"`
+	I float64 `json:"i"`
+	Q float64 `json:"q"`
+}
+
+// Наибольшие значения U, V в YUV и I, Q в YIQ
+const (
+	UMax = 0.436
+	VMax = 0.615
+	IMax = 0.5957
+	QMax = 0.5226
+)
+
+// ---------- YCbCr ----------
+
+// ycbcrScale - размах и смещение кодовых значений Y и Cb/Cr
+func ycbcrScale(limited bool) (yScale, yOffset, cScale float64) {
+	if limited {
+		return 219, 16, 224
+	}
+	return 255, 0, 255
+}
+
+func (c YCbCr) RGB() RGB {
+	kr, kb := lumaCoefficients(c.Standard)
+	yScale, yOffset, cScale := ycbcrScale(c.Limited)
+	y := (c.Y - yOffset) / yScale
+	pb := (c.Cb - 128) / cScale
+	pr := (c.Cr - 128) / cScale
+
+	r := y + 2*(1-kr)*pr
+	b := y + 2*(1-kb)*pb
+	g := (y - kr*r - kb*b) / (1 - kr - kb)
+	return RGB{R: r * 255, G: g * 255, B: b * 255}
+}
+
+// ToYCbCr - цвет в YCbCr стандарта s (полный или узкий диапазон).
+// Значение YCbCr с тем же стандартом и диапазоном возвращается без изменений.
+func ToYCbCr(c Color, s VideoStandard, limited bool) YCbCr {
+	if v, ok := c.(YCbCr); ok && v.Standard == s && v.Limited == limited {
+		return v
+	}
+	p := c.RGB()
+	kr, kb := lumaCoefficients(s)
+	r, g, b := p.R/255, p.G/255, p.B/255
+	y := kr*r + (1-kr-kb)*g + kb*b
+
+	yScale, yOffset, cScale := ycbcrScale(limited)
+	return YCbCr{
+		Y:        yOffset + yScale*y,
+		Cb:       128 + cScale*(b-y)/(2*(1-kb)),
+		Cr:       128 + cScale*(r-y)/(2*(1-kr)),
+		Standard: s,
+		Limited:  limited,
+	}
+}
+
+// ---------- YUV ----------
+
+func (c YUV) RGB() RGB {
+	kr, kb := lumaCoefficients(c.Standard)
+	r := c.Y + c.V*(1-kr)/VMax
+	b := c.Y + c.U*(1-kb)/UMax
+	g := (c.Y - kr*r - kb*b) / (1 - kr - kb)
+	return RGB{R: r * 255, G: g * 255, B: b * 255}
+}
+
+// ToYUV - цвет в YUV с коэффициентами яркости стандарта s
+// (для BT.601 - классические U = 0.492(B'-Y'), V = 0.877(R'-Y'))
+func ToYUV(c Color, s VideoStandard) YUV {
+	if v, ok := c.(YUV); ok && v.Standard == s {
+		return v
+	}
+	p := c.RGB()
+	kr, kb := lumaCoefficients(s)
+	r, g, b := p.R/255, p.G/255, p.B/255
+	y := kr*r + (1-kr-kb)*g + kb*b
+	return YUV{
+		Y:        y,
+		U:        UMax * (b - y) / (1 - kb),
+		V:        VMax * (r - y) / (1 - kr),
+		Standard: s,
+	}
+}
+
+// ---------- YIQ ----------
+
+var rgbToYIQMatrix = matrix3{
+	{0.299, 0.587, 0.114},
+	{0.5959, -0.2746, -0.3213},
+	{0.2115, -0.5227, 0.3112},
+}
+
+var yiqToRGBMatrix = rgbToYIQMatrix.inverse()
+
+func (c YIQ) RGB() RGB {
+	r, g, b := yiqToRGBMatrix.apply(c.Y, c.I, c.Q)
+	return RGB{R: r * 255, G: g * 255, B: b * 255}
+}
+
+func ToYIQ(c Color) YIQ {
+	if v, ok := c.(YIQ); ok {
+		return v
+	}
+	p := c.RGB()
+	y, i, q := rgbToYIQMatrix.apply(p.R/255, p.G/255, p.B/255)
+	return YIQ{Y: y, I: i, Q: q}
+}
@@ -0,0 +1,93 @@
+package colors
+
+import (
+	"math"
+	"testing"
+)
+
+// videoModels - YCbCr во всех стандартах и диапазонах, YUV и YIQ
+func videoModels() []model {
+	var ms []model
+	for _, s := range []VideoStandard{BT601, BT709, BT2020} {
+		s := s
+		for _, limited := range []bool{false, true} {
+			limited := limited
+			ms = append(ms, model{"ycbcr",
+				func(c Color) Color { return ToYCbCr(c, s, limited) },
+				func(c Color) Color { return ToYCbCr(c, s, limited).Rounded() }})
+		}
+		ms = append(ms, model{"yuv",
+			func(c Color) Color { return ToYUV(c, s) },
+			func(c Color) Color { return ToYUV(c, s).Rounded() }})
+	}
+	return append(ms, model{"yiq",
+		func(c Color) Color { return ToYIQ(c) },
+		func(c Color) Color { return ToYIQ(c).Rounded() }})
+}
+
+func TestVideoRoundTrip(t *testing.T) {
+	for i, m := range videoModels() {
+		worstFloat, worstRounded := 0.0, 0.0
+		forEachRGB(5, func(c RGB) {
+			worstFloat = math.Max(worstFloat, rgbError(c, m.to(c).RGB()))
+			worstRounded = math.Max(worstRounded, rgbError(c, m.rounded(c).RGB()))
+		})
+		if worstFloat > floatBound {
+			t.Errorf("#%d %s: float error %g, want <= %g", i, m.name, worstFloat, floatBound)
+		}
+		if worstRounded >= 0.5 {
+			t.Errorf("#%d %s: rounded error %.4f, want < 0.5", i, m.name, worstRounded)
+		}
+	}
+}
+
+func TestVideoKnownValues(t *testing.T) {
+	approx := func(a, b float64) bool { return math.Abs(a-b) <= 1e-3 }
+	white, black, red := RGB{R: 255, G: 255, B: 255}, RGB{}, RGB{R: 255}
+
+	cases := []struct {
+		name      string
+		got, want YCbCr
+	}{
+		{"601 full white", ToYCbCr(white, BT601, false), YCbCr{Y: 255, Cb: 128, Cr: 128}},
+		{"601 full red", ToYCbCr(red, BT601, false), YCbCr{Y: 76.245, Cb: 84.972, Cr: 255.5}},
+		{"709 limited white", ToYCbCr(white, BT709, true), YCbCr{Y: 235, Cb: 128, Cr: 128}},
+		{"709 limited black", ToYCbCr(black, BT709, true), YCbCr{Y: 16, Cb: 128, Cr: 128}},
+		{"709 limited red", ToYCbCr(red, BT709, true), YCbCr{Y: 62.5594, Cb: 102.3358, Cr: 240}},
+		{"2020 full red", ToYCbCr(red, BT2020, false), YCbCr{Y: 66.9885, Cb: 92.3943, Cr: 255.5}},
+	}
+	for _, c := range cases {
+		if !approx(c.got.Y, c.want.Y) || !approx(c.got.Cb, c.want.Cb) || !approx(c.got.Cr, c.want.Cr) {
+			t.Errorf("%s: got %+v, want %+v", c.name, c.got, c.want)
+		}
+	}
+
+	// классические коэффициенты BT.601: U = 0.492(B'-Y'), V = 0.877(R'-Y')
+	if yuv := ToYUV(RGB{B: 255}, BT601); !approx(yuv.U, 0.436) || !approx(yuv.Y, 0.114) {
+		t.Errorf("blue yuv = %+v", yuv)
+	}
+	if yuv := ToYUV(red, BT601); !approx(yuv.V, 0.877283*0.701) {
+		t.Errorf("red yuv = %+v", yuv)
+	}
+	if yiq := ToYIQ(white); !approx(yiq.Y, 1) || !approx(yiq.I, 0) || !approx(yiq.Q, 0) {
+		t.Errorf("white yiq = %+v", yiq)
+	}
+	if yiq := ToYIQ(red); !approx(yiq.I, 0.5959) || !approx(yiq.Q, 0.2115) {
+		t.Errorf("red yiq = %+v", yiq)
+	}
+}
+
+// Значение видеомодели с тем же стандартом возвращается как есть, с другим - пересчитывается
+func TestVideoIdentity(t *testing.T) {
+	c := YCbCr{Y: 300, Cb: -5, Cr: 128, Standard: BT709, Limited: true}
+	if got := ToYCbCr(c, BT709, true); got != c {
+		t.Errorf("ToYCbCr(%v) = %v, want the same value", c, got)
+	}
+	if got := ToYCbCr(c, BT709, false); got == c {
+		t.Errorf("full range from limited returned the same value %v", got)
+	}
+	yuv := YUV{Y: 0.5, U: 1, V: -1, Standard: BT2020}
+	if got := ToYUV(yuv, BT2020); got != yuv {
+		t.Errorf("ToYUV(%v) = %v, want the same value", yuv, got)
+	}
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"strings"
+
+	"github.com/LordVillain/BSUComputerGraphicsLabs/lab1/colors"
+)
+
+// ContrastRequest - цвет текста и фона в любых моделях ConvertRequest
+type ContrastRequest struct {
+	Foreground ConvertRequest `json:"foreground"`
+	Background ConvertRequest `json:"background"`
+	Level      string         `json:"level,omitempty"`  // требуемый уровень WCAG: "AA" (по умолчанию), "AA-large", "AAA", "AAA-large"
+	MinLc      float64        `json:"min_lc,omitempty"` // дополнительно: минимальный |Lc| по APCA (например 60 или 75)
+}
+
+// ContrastResponse - контраст по WCAG 2.x и APCA и, при необходимости, исправленный цвет текста
+type ContrastResponse struct {
+	Foreground ConvertResponse `json:"foreground"`
+	Background ConvertResponse `json:"background"`
+
+	Ratio    float64 `json:"ratio"` // WCAG 2.x, 1..21
+	AA       bool    `json:"aa"`
+	AALarge  bool    `json:"aa_large"`
+	AAA      bool    `json:"aaa"`
+	AAALarge bool    `json:"aaa_large"`
+	APCA     float64 `json:"apca_lc"` // APCA Lc: > 0 - темный текст на светлом фоне, < 0 - наоборот
+
+	Level      string           `json:"level"`
+	Passes     bool             `json:"passes"`               // выполнены ли level и min_lc
+	Suggestion *ConvertResponse `json:"suggestion,omitempty"` // ближайший (по ΔE2000) цвет текста, проходящий проверку
+	Note       string           `json:"note,omitempty"`
+}
+
+// Пороговые значения WCAG 2.x
+var wcagLevels = map[string]float64{
+	"AA":        4.5,
+	"AA-LARGE":  3,
+	"AAA":       7,
+	"AAA-LARGE": 4.5,
+}
+
+func contrastHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req ContrastRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid json: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := checkContrast(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+func checkContrast(req ContrastRequest) (ContrastResponse, error) {
+	if req.Level == "" {
+		req.Level = "AA"
+	}
+	minRatio, ok := wcagLevels[strings.ToUpper(req.Level)]
+	if !ok {
+		return ContrastResponse{}, errors.New("level must be one of: AA, AA-large, AAA, AAA-large")
+	}
+	if req.MinLc < 0 || req.MinLc > 108 {
+		return ContrastResponse{}, errors.New("min_lc must be in 0..108")
+	}
+
+	bg, err := convertColor(req.Background)
+	if err != nil {
+		return ContrastResponse{}, errors.New("background: " + err.Error())
+	}
+	// полупрозрачный текст оцениваем после наложения на фон
+	fgReq := req.Foreground
+	fgReq.Background = &req.Background
+	fg, err := convertColor(fgReq)
+	if err != nil {
+		return ContrastResponse{}, errors.New("foreground: " + err.Error())
+	}
+
+	bgRGB := bg.RGB.color()
+	flat := fg.Flattened.color()
+
+	ratio := colors.WCAGContrast(flat, bgRGB)
+	lc := colors.APCAContrast(flat, bgRGB)
+
+	resp := ContrastResponse{
+		Foreground: fg,
+		Background: bg,
+		Ratio:      roundFloat(ratio, 2),
+		AA:         ratio >= 4.5,
+		AALarge:    ratio >= 3,
+		AAA:        ratio >= 7,
+		AAALarge:   ratio >= 4.5,
+		APCA:       roundFloat(lc, 1),
+		Level:      req.Level,
+	}
+
+	passes := func(c colors.RGB) bool {
+		return colors.WCAGContrast(c, bgRGB) >= minRatio &&
+			math.Abs(colors.APCAContrast(c, bgRGB)) >= req.MinLc
+	}
+	resp.Passes = passes(flat)
+	if resp.Passes {
+		return resp, nil
+	}
+
+	lab, ok := suggestLightness(fg.Lab, fg.Alpha, bgRGB, passes)
+	if !ok {
+		resp.Note = "no lightness of this hue reaches the requested contrast on this background"
+		return resp, nil
+	}
+	sugReq := labRequest(lab)
+	sugReq.Gamut = "chroma"
+	sugReq.Alpha = fgReq.Alpha
+	sugReq.Background = fgReq.Background
+	sug, err := convertColor(sugReq)
+	if err != nil {
+		return ContrastResponse{}, err
+	}
+	resp.Suggestion = &sug
+	return resp, nil
+}
+
+// suggestLightness ищет светлоту L* текста, ближайшую к исходной, при которой выполняется passes.
+// Тон и насыщенность сохраняются (лишняя насыщенность срезается при приведении в охват),
+// поэтому яркость монотонно зависит от L* и поиск можно вести делением пополам -
+// отдельно в сторону затемнения и осветления. Из двух вариантов берется ближайший по ΔE2000.
+func suggestLightness(lab colors.Lab, alpha float64, bg colors.RGB, passes func(c colors.RGB) bool) (suggested colors.Lab, ok bool) {
+	check := func(L float64) bool {
+		c := colors.ReduceChroma(colors.Lab{L: L, A: lab.A, B: lab.B})
+		return passes(colors.CompositeOver(c, alpha, bg))
+	}
+	// search находит ближайшую к start точку отрезка [start, end], где check выполняется
+	search := func(start, end float64) (float64, bool) {
+		if !check(end) {
+			return 0, false
+		}
+		bad, good := start, end
+		for math.Abs(good-bad) > 0.01 {
+			mid := (bad + good) / 2
+			if check(mid) {
+				good = mid
+			} else {
+				bad = mid
+			}
+		}
+		return good, true
+	}
+
+	best := math.Inf(1)
+	for _, end := range []float64{0, 100} {
+		L, found := search(lab.L, end)
+		if !found {
+			continue
+		}
+		cand := colors.Lab{L: L, A: lab.A, B: lab.B}
+		if d := colors.DeltaE2000(lab, cand); d < best {
+			best, suggested, ok = d, cand, true
+		}
+	}
+	return suggested, ok
+}
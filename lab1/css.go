@@ -0,0 +1,349 @@
+package main
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/LordVillain/BSUComputerGraphicsLabs/lab1/colors"
+)
+
+// CSSStrings - готовые для вставки в CSS записи цвета во всех моделях, у которых есть CSS-синтаксис
+type CSSStrings struct {
+	Hex   string `json:"hex"`
+	RGB   string `json:"rgb"`
+	HSL   string `json:"hsl"`
+	HWB   string `json:"hwb"`
+	Lab   string `json:"lab"`
+	LCh   string `json:"lch"`
+	OKLab string `json:"oklab"`
+	OKLCh string `json:"oklch"`
+	XYZ   string `json:"xyz"`  // color(xyz-d65 ...)
+	CMYK  string `json:"cmyk"` // device-cmyk(...) из CSS Color 5
+}
+
+//go:embed names/css.txt
+var cssNamesFile string
+
+// именованные цвета CSS: имя -> #rrggbb
+var cssNamedColors = loadNamedColors(cssNamesFile)
+
+// loadNamedColors - словарь имя -> #rrggbb из файла палитры (формат разбирает parsePalette)
+func loadNamedColors(data string) map[string]string {
+	names := map[string]string{}
+	for _, e := range mustParsePalette(data) {
+		names[e.name] = e.hex
+	}
+	return names
+}
+
+// ---------- Разбор CSS Color Level 4 ----------
+
+// parseCSSColor переводит CSS-строку цвета в эквивалентный ConvertRequest (модель + значения)
+// и альфа-канал 0..1. Поддерживаются: #hex, именованные цвета, transparent,
+// rgb()/rgba(), hsl()/hsla(), hwb(), lab(), lch(), oklab(), oklch()
+// и color(srgb | srgb-linear | display-p3 | a98-rgb | rec2020 | xyz | xyz-d65 ...).
+func parseCSSColor(s string) (req ConvertRequest, alpha float64, err error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" {
+		return req, 0, errors.New("empty css color")
+	}
+
+	if strings.HasPrefix(s, "#") {
+		r, g, b, a, err := parseHexColor(s)
+		if err != nil {
+			return req, 0, err
+		}
+		return rgbRequest(r, g, b), a, nil
+	}
+
+	open := strings.IndexByte(s, '(')
+	if open < 0 {
+		if s == "transparent" {
+			return rgbRequest(0, 0, 0), 0, nil
+		}
+		hex, ok := cssNamedColors[s]
+		if !ok {
+			return req, 0, fmt.Errorf("unknown css color %q", s)
+		}
+		r, g, b, a, err := parseHexColor(hex)
+		return rgbRequest(r, g, b), a, err
+	}
+	if !strings.HasSuffix(s, ")") {
+		return req, 0, fmt.Errorf("css color %q: missing ')'", s)
+	}
+
+	fn := strings.TrimSpace(s[:open])
+	args, alphaTok, err := splitCSSArgs(s[open+1 : len(s)-1])
+	if err != nil {
+		return req, 0, fmt.Errorf("css color %q: %v", s, err)
+	}
+
+	alpha = 1
+	if alphaTok != "" {
+		if alpha, err = parseCSSNumber(alphaTok, 1); err != nil {
+			return req, 0, fmt.Errorf("css color %q: alpha: %v", s, err)
+		}
+		alpha = clampFloat(alpha, 0, 1)
+	}
+
+	if fn == "color" {
+		req, err = parseCSSColorFunction(args)
+		if err != nil {
+			return req, 0, fmt.Errorf("css color %q: %v", s, err)
+		}
+		return req, alpha, nil
+	}
+
+	if len(args) != 3 {
+		return req, 0, fmt.Errorf("css color %q: expected 3 components, got %d", s, len(args))
+	}
+
+	// описание компонент: как читать каждую (опорное значение для процентов или тон)
+	const hue = -1
+	var refs [3]float64
+	switch fn {
+	case "rgb", "rgba":
+		refs = [3]float64{255, 255, 255}
+	case "hsl", "hsla", "hwb":
+		refs = [3]float64{hue, 100, 100}
+	case "lab":
+		refs = [3]float64{100, 125, 125}
+	case "lch":
+		refs = [3]float64{100, 150, hue}
+	case "oklab":
+		refs = [3]float64{1, 0.4, 0.4}
+	case "oklch":
+		refs = [3]float64{1, 0.4, hue}
+	default:
+		return req, 0, fmt.Errorf("unsupported css color function %q", fn)
+	}
+
+	var v [3]float64
+	for i, tok := range args {
+		if refs[i] == hue {
+			v[i], err = parseCSSHue(tok)
+		} else {
+			v[i], err = parseCSSNumber(tok, refs[i])
+		}
+		if err != nil {
+			return req, 0, fmt.Errorf("css color %q: %v", s, err)
+		}
+	}
+
+	switch fn {
+	case "rgb", "rgba":
+		req = rgbRequest(v[0], v[1], v[2])
+	case "hsl", "hsla":
+		req = ConvertRequest{Model: "hsl", Values: map[string]float64{"h": v[0], "s": v[1], "l": v[2]}}
+	case "hwb":
+		req = ConvertRequest{Model: "hwb", Values: map[string]float64{"h": v[0], "w": v[1], "b": v[2]}}
+	case "lab":
+		req = cssLabRequest(colors.Lab{L: v[0], A: v[1], B: v[2]})
+	case "lch":
+		req = cssLabRequest(colors.ToLab(colors.LCh{L: v[0], C: math.Max(v[1], 0), H: v[2]}))
+	case "oklab":
+		req = ConvertRequest{Model: "oklab", Values: map[string]float64{"l": v[0], "a": v[1], "b": v[2]}}
+	case "oklch":
+		req = ConvertRequest{Model: "oklch", Values: map[string]float64{"l": v[0], "c": math.Max(v[1], 0), "h": v[2]}}
+	}
+	return req, alpha, nil
+}
+
+// Рабочие RGB-пространства в записи CSS color()
+var cssRGBSpaces = map[string]*colors.RGBSpace{
+	"display-p3": colors.DisplayP3,
+	"a98-rgb":    colors.AdobeRGB,
+	"rec2020":    colors.Rec2020,
+}
+
+// parseCSSColorFunction - color(<пространство> c1 c2 c3)
+func parseCSSColorFunction(args []string) (ConvertRequest, error) {
+	if len(args) != 4 {
+		return ConvertRequest{}, errors.New("color() expects a color space and 3 components")
+	}
+	var v [3]float64
+	for i, tok := range args[1:] {
+		var err error
+		if v[i], err = parseCSSNumber(tok, 1); err != nil {
+			return ConvertRequest{}, err
+		}
+	}
+	switch args[0] {
+	case "srgb":
+		return rgbRequest(v[0]*255, v[1]*255, v[2]*255), nil
+	case "srgb-linear":
+		return rgbRequest(colors.LinearToSRGB(v[0])*255, colors.LinearToSRGB(v[1])*255, colors.LinearToSRGB(v[2])*255), nil
+	case "xyz", "xyz-d65":
+		return ConvertRequest{Model: "xyz", Values: map[string]float64{"x": v[0] * 100, "y": v[1] * 100, "z": v[2] * 100}}, nil
+	case "display-p3", "a98-rgb", "rec2020":
+		// широкие пространства переводим в XYZ: rgb_space запроса относится к значениям rgb, а не к CSS
+		xyz := colors.ToXYZ(colors.SpaceRGB{R: v[0] * 255, G: v[1] * 255, B: v[2] * 255, Space: cssRGBSpaces[args[0]]})
+		return ConvertRequest{Model: "xyz", Values: map[string]float64{"x": xyz.X, "y": xyz.Y, "z": xyz.Z}}, nil
+	}
+	return ConvertRequest{}, fmt.Errorf("unsupported color space %q", args[0])
+}
+
+// CSS lab()/lch() задаются относительно D50 - переводим в XYZ D65
+func cssLabRequest(lab colors.Lab) ConvertRequest {
+	xyz := colors.AdaptD50ToD65(colors.XYZFromLab(lab, colors.WhiteD50))
+	return ConvertRequest{Model: "xyz", Values: map[string]float64{"x": xyz.X, "y": xyz.Y, "z": xyz.Z}}
+}
+
+func rgbRequest(r, g, b float64) ConvertRequest {
+	return ConvertRequest{Model: "rgb", Values: map[string]float64{"r": r, "g": g, "b": b}}
+}
+
+// splitCSSArgs делит аргументы функции на компоненты и альфу.
+// Понимает и современный синтаксис ("255 136 0 / 50%"), и устаревший через запятые ("255, 136, 0, 0.5").
+func splitCSSArgs(body string) (args []string, alpha string, err error) {
+	if i := strings.IndexByte(body, '/'); i >= 0 {
+		alpha = strings.TrimSpace(body[i+1:])
+		body = body[:i]
+		if alpha == "" {
+			return nil, "", errors.New("missing alpha after '/'")
+		}
+	}
+
+	if strings.Contains(body, ",") {
+		for _, part := range strings.Split(body, ",") {
+			args = append(args, strings.TrimSpace(part))
+		}
+		if len(args) == 4 && alpha == "" {
+			alpha, args = args[3], args[:3]
+		}
+	} else {
+		args = strings.Fields(body)
+	}
+
+	for _, a := range args {
+		if a == "" {
+			return nil, "", errors.New("empty component")
+		}
+	}
+	return args, alpha, nil
+}
+
+// parseCSSNumber - число или процент (100% = ref); "none" означает 0
+func parseCSSNumber(tok string, ref float64) (float64, error) {
+	if tok == "none" {
+		return 0, nil
+	}
+	if strings.HasSuffix(tok, "%") {
+		v, ok := parseCSSFloat(strings.TrimSuffix(tok, "%"))
+		if !ok {
+			return 0, fmt.Errorf("invalid percentage %q", tok)
+		}
+		return v / 100 * ref, nil
+	}
+	v, ok := parseCSSFloat(tok)
+	if !ok {
+		return 0, fmt.Errorf("invalid number %q", tok)
+	}
+	return v, nil
+}
+
+// parseCSSFloat - конечное число; ParseFloat принимает еще nan и inf, которых в CSS нет
+func parseCSSFloat(tok string) (float64, bool) {
+	v, err := strconv.ParseFloat(tok, 64)
+	return v, err == nil && !math.IsNaN(v) && !math.IsInf(v, 0)
+}
+
+// parseCSSHue - угол в градусах; допускаются единицы deg, rad, grad, turn
+func parseCSSHue(tok string) (float64, error) {
+	if tok == "none" {
+		return 0, nil
+	}
+	units := []struct {
+		suffix string
+		scale  float64
+	}{
+		{"deg", 1},
+		{"grad", 0.9},
+		{"rad", 180 / math.Pi},
+		{"turn", 360},
+	}
+	scale := 1.0
+	for _, u := range units {
+		if strings.HasSuffix(tok, u.suffix) {
+			tok, scale = strings.TrimSuffix(tok, u.suffix), u.scale
+			break
+		}
+	}
+	v, ok := parseCSSFloat(tok)
+	if !ok {
+		return 0, fmt.Errorf("invalid hue %q", tok)
+	}
+	return v * scale, nil
+}
+
+// parseHexColor - #rgb, #rgba, #rrggbb, #rrggbbaa
+func parseHexColor(s string) (r, g, b, a float64, err error) {
+	h := strings.TrimPrefix(s, "#")
+	if len(h) == 3 || len(h) == 4 {
+		var long strings.Builder
+		for _, c := range h {
+			long.WriteRune(c)
+			long.WriteRune(c)
+		}
+		h = long.String()
+	}
+	if len(h) != 6 && len(h) != 8 {
+		return 0, 0, 0, 0, fmt.Errorf("invalid hex color %q", s)
+	}
+	v, err := strconv.ParseUint(h, 16, 32)
+	if err != nil {
+		return 0, 0, 0, 0, fmt.Errorf("invalid hex color %q", s)
+	}
+	a = 1
+	if len(h) == 8 {
+		a = float64(v&0xff) / 255
+		v >>= 8
+	}
+	return float64(v >> 16 & 0xff), float64(v >> 8 & 0xff), float64(v & 0xff), a, nil
+}
+
+// ---------- Форматирование ----------
+
+// formatCSS собирает CSS-записи цвета из готового ответа конвертации.
+// Записи, которые могут выражать цвета вне охвата sRGB (lab, lch, xyz, oklab, oklch),
+// строятся по соответствующим значениям ответа, остальные - по итоговому RGB.
+func formatCSS(resp ConvertResponse) CSSStrings {
+	rgb := resp.RGB
+	// lab()/lch() в CSS относительно D50
+	lab := colors.LabFromXYZ(colors.AdaptD65ToD50(resp.XYZ), colors.WhiteD50)
+	lch := colors.ToLCh(lab)
+
+	// альфа добавляется только для полупрозрачных цветов
+	a := ""
+	hexA := ""
+	if resp.Alpha < 1 {
+		a = " / " + cssNum(resp.Alpha, 4)
+		hexA = fmt.Sprintf("%02x", int(math.Round(resp.Alpha*255)))
+	}
+
+	return CSSStrings{
+		Hex:   fmt.Sprintf("#%02x%02x%02x%s", rgb.R, rgb.G, rgb.B, hexA),
+		RGB:   fmt.Sprintf("rgb(%d %d %d%s)", rgb.R, rgb.G, rgb.B, a),
+		HSL:   fmt.Sprintf("hsl(%s %s%% %s%%%s)", cssNum(resp.HSL.H, 2), cssNum(resp.HSL.S, 2), cssNum(resp.HSL.L, 2), a),
+		HWB:   fmt.Sprintf("hwb(%s %s%% %s%%%s)", cssNum(resp.HWB.H, 2), cssNum(resp.HWB.W, 2), cssNum(resp.HWB.B, 2), a),
+		Lab:   fmt.Sprintf("lab(%s %s %s%s)", cssNum(lab.L, 2), cssNum(lab.A, 2), cssNum(lab.B, 2), a),
+		LCh:   fmt.Sprintf("lch(%s %s %s%s)", cssNum(lch.L, 2), cssNum(lch.C, 2), cssNum(lch.H, 2), a),
+		OKLab: fmt.Sprintf("oklab(%s %s %s%s)", cssNum(resp.OKLab.L, 4), cssNum(resp.OKLab.A, 4), cssNum(resp.OKLab.B, 4), a),
+		OKLCh: fmt.Sprintf("oklch(%s %s %s%s)", cssNum(resp.OKLCh.L, 4), cssNum(resp.OKLCh.C, 4), cssNum(resp.OKLCh.H, 2), a),
+		XYZ:   fmt.Sprintf("color(xyz-d65 %s %s %s%s)", cssNum(resp.XYZ.X/100, 4), cssNum(resp.XYZ.Y/100, 4), cssNum(resp.XYZ.Z/100, 4), a),
+		CMYK:  fmt.Sprintf("device-cmyk(%s%% %s%% %s%% %s%%%s)", cssNum(resp.CMYK.C, 2), cssNum(resp.CMYK.M, 2), cssNum(resp.CMYK.Y, 2), cssNum(resp.CMYK.K, 2), a),
+	}
+}
+
+// cssNum - число без лишних нулей и без "-0"
+func cssNum(v float64, prec int) string {
+	v = roundFloat(v, prec)
+	if v == 0 {
+		v = 0
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
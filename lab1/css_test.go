@@ -0,0 +1,44 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseCSSColor(t *testing.T) {
+	for _, tc := range []struct {
+		css   string
+		model string
+		want  map[string]float64
+		alpha float64
+	}{
+		{"#ff880080", "rgb", map[string]float64{"r": 255, "g": 136, "b": 0}, 128.0 / 255},
+		{"rgb(255 136 0 / 50%)", "rgb", map[string]float64{"r": 255, "g": 136, "b": 0}, 0.5},
+		{"rgba(100%, 0%, 0%, 0.25)", "rgb", map[string]float64{"r": 255, "g": 0, "b": 0}, 0.25},
+		{"hsl(0.5turn 100% 50%)", "hsl", map[string]float64{"h": 180, "l": 50, "s": 100}, 1},
+	} {
+		req, alpha, err := parseCSSColor(tc.css)
+		if err != nil {
+			t.Errorf("%s: %v", tc.css, err)
+			continue
+		}
+		if req.Model != tc.model || math.Abs(alpha-tc.alpha) > 1e-9 {
+			t.Errorf("%s: model %s, alpha %v", tc.css, req.Model, alpha)
+		}
+		for k, v := range tc.want {
+			if math.Abs(req.Values[k]-v) > 1e-9 {
+				t.Errorf("%s: %s = %v, want %v", tc.css, k, req.Values[k], v)
+			}
+		}
+	}
+
+	// ParseFloat понимает nan и inf, CSS - нет
+	for _, s := range []string{
+		"", "#12", "rgb(1 2)", "rgb(1 2 3", "plasma",
+		"rgb(nan 0 0)", "rgb(inf 0 0)", "rgb(0 0 0 / nan)", "hsl(infinity 50% 50%)", "lab(50 -inf% 0)",
+	} {
+		if _, _, err := parseCSSColor(s); err == nil {
+			t.Errorf("%q: expected error", s)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/LordVillain/BSUComputerGraphicsLabs/lab1/colors"
+)
+
+// SimulateRequest - один цвет или список цветов (палитра) для симуляции нарушений цветового зрения
+type SimulateRequest struct {
+	Color    *ConvertRequest  `json:"color,omitempty"`
+	Colors   []ConvertRequest `json:"colors,omitempty"`
+	Severity *float64         `json:"severity,omitempty"` // степень нарушения 0..1 (по умолчанию 1 - полная дихромазия)
+}
+
+// SimulatedColor - исходный цвет и то, как его видят люди с разными нарушениями
+type SimulatedColor struct {
+	Original      ConvertResponse `json:"original"`
+	Protanopia    ConvertResponse `json:"protanopia"`
+	Deuteranopia  ConvertResponse `json:"deuteranopia"`
+	Tritanopia    ConvertResponse `json:"tritanopia"`
+	Achromatopsia ConvertResponse `json:"achromatopsia"`
+}
+
+type SimulateResponse struct {
+	Severity float64          `json:"severity"`
+	Colors   []SimulatedColor `json:"colors"`
+}
+
+func simulateHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req SimulateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid json: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := simulateColors(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+func simulateColors(req SimulateRequest) (SimulateResponse, error) {
+	colors := req.Colors
+	if req.Color != nil {
+		colors = append([]ConvertRequest{*req.Color}, colors...)
+	}
+	if len(colors) == 0 {
+		return SimulateResponse{}, errors.New("color or colors is required")
+	}
+
+	severity := 1.0
+	if req.Severity != nil {
+		severity = *req.Severity
+	}
+	if severity < 0 || severity > 1 {
+		return SimulateResponse{}, errors.New("severity must be in 0..1")
+	}
+
+	resp := SimulateResponse{Severity: severity}
+	for i, c := range colors {
+		sim, err := simulateColor(c, severity)
+		if err != nil {
+			return SimulateResponse{}, fmt.Errorf("colors[%d]: %v", i, err)
+		}
+		resp.Colors = append(resp.Colors, sim)
+	}
+	return resp, nil
+}
+
+func simulateColor(req ConvertRequest, severity float64) (SimulatedColor, error) {
+	orig, err := convertColor(req)
+	if err != nil {
+		return SimulatedColor{}, err
+	}
+
+	// моделируем по итоговому (приведенному в охват) цвету
+	base := orig.RGB.color()
+	convert := func(c colors.RGB) (ConvertResponse, error) {
+		sim := rgbRequest(c.R, c.G, c.B)
+		sim.Gamut = req.Gamut
+		sim.Profile = req.Profile
+		sim.Alpha = req.Alpha
+		sim.Background = req.Background
+		return convertColor(sim)
+	}
+
+	sim := SimulatedColor{Original: orig}
+	targets := []struct {
+		dst *ConvertResponse
+		d   colors.Deficiency
+	}{
+		{&sim.Protanopia, colors.Protanopia},
+		{&sim.Deuteranopia, colors.Deuteranopia},
+		{&sim.Tritanopia, colors.Tritanopia},
+		{&sim.Achromatopsia, colors.Achromatopsia},
+	}
+	for _, t := range targets {
+		if *t.dst, err = convert(colors.SimulateCVD(base, t.d, severity)); err != nil {
+			return SimulatedColor{}, err
+		}
+	}
+	return sim, nil
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/LordVillain/BSUComputerGraphicsLabs/lab1/colors"
+)
+
+// DeltaRequest - два цвета в любых моделях, поддерживаемых ConvertRequest
+type DeltaRequest struct {
+	A ConvertRequest `json:"a"`
+	B ConvertRequest `json:"b"`
+}
+
+// DeltaResponse - цветовые различия между A и B и сами цвета во всех моделях
+type DeltaResponse struct {
+	A        ConvertResponse `json:"a"`
+	B        ConvertResponse `json:"b"`
+	DeltaE76 float64         `json:"delta_e76"`
+	DeltaE94 float64         `json:"delta_e94"`
+	DeltaE00 float64         `json:"delta_e2000"`
+}
+
+func deltaHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req DeltaRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid json: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	respA, err := convertColor(req.A)
+	if err != nil {
+		http.Error(w, "a: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	respB, err := convertColor(req.B)
+	if err != nil {
+		http.Error(w, "b: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Различие считаем по запрошенным цветам, до приведения в охват sRGB,
+	// чтобы Lab-значения вне охвата сравнивались как есть.
+	labA, err := requestLab(req.A)
+	if err != nil {
+		http.Error(w, "a: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	labB, err := requestLab(req.B)
+	if err != nil {
+		http.Error(w, "b: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp := DeltaResponse{
+		A:        respA,
+		B:        respB,
+		DeltaE76: roundFloat(colors.DeltaE76(labA, labB), 4),
+		DeltaE94: roundFloat(colors.DeltaE94(labA, labB), 4),
+		DeltaE00: roundFloat(colors.DeltaE2000(labA, labB), 4),
+	}
+	// Lab обоих цветов конечны, но формулы (C⁷ в ΔE2000) могут переполниться
+	if !finiteFloats(resp.DeltaE76, resp.DeltaE94, resp.DeltaE00) {
+		http.Error(w, errOutOfRange.Error(), http.StatusBadRequest)
+		return
+	}
+
+	data, err := json.Marshal(resp)
+	if err != nil {
+		http.Error(w, "failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(append(data, '\n'))
+}
+
+// requestLab - Lab цвета из запроса (для модели "lab" значения берутся как есть).
+// Цвет до приведения в охват не проходит проверку convertColor, поэтому Lab проверяется здесь.
+func requestLab(req ConvertRequest) (colors.Lab, error) {
+	color, err := requestColor(req)
+	if err != nil {
+		return colors.Lab{}, err
+	}
+	lab := colors.ToLab(color)
+	if !finiteFloats(lab.L, lab.A, lab.B) {
+		return colors.Lab{}, errOutOfRange
+	}
+	return lab, nil
+}
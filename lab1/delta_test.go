@@ -0,0 +1,32 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDeltaHandler(t *testing.T) {
+	post := func(body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		deltaHandler(rec, httptest.NewRequest(http.MethodPost, "/api/delta", strings.NewReader(body)))
+		return rec
+	}
+
+	rec := post(`{"a":{"css":"red"},"b":{"css":"red"}}`)
+	var resp DeltaResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); rec.Code != http.StatusOK || err != nil {
+		t.Fatalf("red-red: status %d, %v", rec.Code, err)
+	}
+	if resp.DeltaE76 != 0 || resp.DeltaE94 != 0 || resp.DeltaE00 != 0 {
+		t.Errorf("red-red: %+v", resp)
+	}
+
+	// огромное значение проходит convertColor (обрезается в охват), но ΔE от него не конечна
+	rec = post(`{"a":{"model":"rgb","values":{"r":1e120,"g":0,"b":0}},"b":{"css":"red"}}`)
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "out of range") {
+		t.Errorf("r=1e120: status %d, %q", rec.Code, rec.Body.String())
+	}
+}
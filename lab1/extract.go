@@ -0,0 +1,400 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"math"
+	"math/rand"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/LordVillain/BSUComputerGraphicsLabs/lab1/colors"
+)
+
+// ExtractResponse - основные цвета изображения в порядке убывания доли пикселей
+type ExtractResponse struct {
+	Method string           `json:"method"`
+	Space  string           `json:"space"`
+	Pixels int              `json:"pixels"` // сколько непрозрачных пикселей учтено (после прореживания)
+	Colors []ExtractedColor `json:"colors"`
+}
+
+type ExtractedColor struct {
+	Share float64         `json:"share"` // доля пикселей 0..1
+	Color ConvertResponse `json:"color"`
+}
+
+// Ограничения: размер файла, число пикселей изображения, число учитываемых пикселей и цветов
+const (
+	maxExtractFileSize = 32 << 20
+	maxExtractPixels   = 50_000_000
+	maxExtractSamples  = 250_000
+	maxExtractColors   = 32
+	kmeansIterations   = 100
+)
+
+// extractPoint - группа близких пикселей: средний цвет в координатах пространства и число пикселей
+type extractPoint struct {
+	v [3]float64
+	w float64
+}
+
+// extractHandler принимает multipart-форму: image - файл (PNG, JPEG, GIF),
+// k - число цветов (по умолчанию 5), method - "kmeans" (по умолчанию) или "mediancut",
+// space - пространство кластеризации: "srgb", "linear", "lab", "oklab" (по умолчанию).
+func extractHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxExtractFileSize)
+	file, _, err := r.FormFile("image")
+	if err != nil {
+		http.Error(w, "failed to read image: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	k := 5
+	if s := r.FormValue("k"); s != "" {
+		if k, err = strconv.Atoi(s); err != nil {
+			http.Error(w, "k must be an integer", http.StatusBadRequest)
+			return
+		}
+	}
+
+	img, err := decodeImage(file)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := extractColors(img, k, r.FormValue("method"), r.FormValue("space"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// decodeImage декодирует изображение, заранее проверяя его размеры по заголовку
+func decodeImage(r io.Reader) (image.Image, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.New("invalid image format")
+	}
+	if cfg.Width*cfg.Height > maxExtractPixels {
+		return nil, fmt.Errorf("image is larger than %d pixels", maxExtractPixels)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.New("invalid image format")
+	}
+	return img, nil
+}
+
+func extractColors(img image.Image, k int, method, space string) (ExtractResponse, error) {
+	if k < 1 || k > maxExtractColors {
+		return ExtractResponse{}, fmt.Errorf("k must be in 1..%d", maxExtractColors)
+	}
+	if method == "" {
+		method = "kmeans"
+	}
+	if method != "kmeans" && method != "mediancut" {
+		return ExtractResponse{}, errors.New("method must be one of: kmeans, mediancut")
+	}
+	if space == "" {
+		space = "oklab"
+	}
+	if space != "srgb" && space != "linear" && space != "lab" && space != "oklab" {
+		return ExtractResponse{}, errors.New("space must be one of: srgb, linear, lab, oklab")
+	}
+
+	points, pixels := samplePixels(img, space)
+	if pixels == 0 {
+		return ExtractResponse{}, errors.New("image has no opaque pixels")
+	}
+
+	var clusters []extractPoint
+	if method == "kmeans" {
+		clusters = kmeans(points, k)
+	} else {
+		clusters = medianCut(points, k)
+	}
+	sort.SliceStable(clusters, func(i, j int) bool { return clusters[i].w > clusters[j].w })
+
+	resp := ExtractResponse{Method: method, Space: space, Pixels: pixels}
+	for _, c := range clusters {
+		color, err := convertColor(extractRequest(space, c.v))
+		if err != nil {
+			return ExtractResponse{}, err
+		}
+		resp.Colors = append(resp.Colors, ExtractedColor{Share: roundFloat(c.w/float64(pixels), 4), Color: color})
+	}
+	return resp, nil
+}
+
+// samplePixels прореживает большое изображение до maxExtractSamples пикселей
+// и собирает гистограмму: пиксели с одинаковыми старшими 5 битами каналов
+// объединяются в одну точку с их средним цветом. Прозрачные больше чем наполовину пиксели пропускаются.
+func samplePixels(img image.Image, space string) (points []extractPoint, pixels int) {
+	b := img.Bounds()
+	step := 1
+	if n := b.Dx() * b.Dy(); n > maxExtractSamples {
+		step = int(math.Ceil(math.Sqrt(float64(n) / maxExtractSamples)))
+	}
+
+	type bin struct{ r, g, b, n float64 }
+	bins := map[uint32]*bin{}
+	for y := b.Min.Y; y < b.Max.Y; y += step {
+		for x := b.Min.X; x < b.Max.X; x += step {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			if c.A < 128 {
+				continue
+			}
+			key := uint32(c.R>>3)<<10 | uint32(c.G>>3)<<5 | uint32(c.B>>3)
+			e := bins[key]
+			if e == nil {
+				e = &bin{}
+				bins[key] = e
+			}
+			e.r += float64(c.R)
+			e.g += float64(c.G)
+			e.b += float64(c.B)
+			e.n++
+			pixels++
+		}
+	}
+
+	// порядок точек не должен зависеть от обхода map - иначе k-means дает разные результаты
+	keys := make([]uint32, 0, len(bins))
+	for key := range bins {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	for _, key := range keys {
+		e := bins[key]
+		rgb := colors.RGB{R: e.r / e.n, G: e.g / e.n, B: e.b / e.n}
+		points = append(points, extractPoint{v: extractCoords(rgb, space), w: e.n})
+	}
+	return points, pixels
+}
+
+// extractCoords - координаты цвета в пространстве кластеризации
+func extractCoords(rgb colors.RGB, space string) [3]float64 {
+	switch space {
+	case "linear":
+		return [3]float64{colors.SRGBToLinear(rgb.R / 255), colors.SRGBToLinear(rgb.G / 255), colors.SRGBToLinear(rgb.B / 255)}
+	case "lab":
+		lab := colors.ToLab(rgb)
+		return [3]float64{lab.L, lab.A, lab.B}
+	case "oklab":
+		lab := colors.ToOKLab(rgb)
+		return [3]float64{lab.L, lab.A, lab.B}
+	}
+	return [3]float64{rgb.R, rgb.G, rgb.B}
+}
+
+// extractRequest - центр кластера как ConvertRequest
+func extractRequest(space string, v [3]float64) ConvertRequest {
+	if space == "lab" {
+		return labRequest(colors.Lab{L: v[0], A: v[1], B: v[2]})
+	}
+	return gradientRequest(space, v)
+}
+
+func dist2(a, b [3]float64) float64 {
+	d0, d1, d2 := a[0]-b[0], a[1]-b[1], a[2]-b[2]
+	return d0*d0 + d1*d1 + d2*d2
+}
+
+// weightedMean - средняя точка группы с учетом числа пикселей
+func weightedMean(points []extractPoint) extractPoint {
+	var m extractPoint
+	for _, p := range points {
+		for i := range m.v {
+			m.v[i] += p.v[i] * p.w
+		}
+		m.w += p.w
+	}
+	for i := range m.v {
+		m.v[i] /= m.w
+	}
+	return m
+}
+
+// ---------- k-means ----------
+
+// kmeans - взвешенный алгоритм Ллойда с начальными центрами k-means++.
+// Генератор случайных чисел с фиксированным зерном: одно и то же изображение дает один и тот же результат.
+func kmeans(points []extractPoint, k int) []extractPoint {
+	if len(points) <= k {
+		return append([]extractPoint(nil), points...)
+	}
+	rnd := rand.New(rand.NewSource(1))
+
+	// первый центр - самая многочисленная группа, следующие - с вероятностью ~ w·d²
+	centers := make([][3]float64, 0, k)
+	first := 0
+	for i, p := range points {
+		if p.w > points[first].w {
+			first = i
+		}
+	}
+	centers = append(centers, points[first].v)
+	d := make([]float64, len(points))
+	for i, p := range points {
+		d[i] = dist2(p.v, centers[0])
+	}
+	for len(centers) < k {
+		total := 0.0
+		for i, p := range points {
+			total += p.w * d[i]
+		}
+		if total == 0 {
+			break // различных цветов меньше k
+		}
+		target := rnd.Float64() * total
+		next := len(points) - 1
+		for i, p := range points {
+			target -= p.w * d[i]
+			if target <= 0 {
+				next = i
+				break
+			}
+		}
+		centers = append(centers, points[next].v)
+		for i, p := range points {
+			d[i] = math.Min(d[i], dist2(p.v, points[next].v))
+		}
+	}
+
+	assign := make([]int, len(points))
+	for i := range assign {
+		assign[i] = -1
+	}
+	clusters := make([]extractPoint, len(centers))
+	for iter := 0; iter < kmeansIterations; iter++ {
+		changed := false
+		for i, p := range points {
+			best, bestD := 0, math.Inf(1)
+			for j, c := range centers {
+				if dd := dist2(p.v, c); dd < bestD {
+					best, bestD = j, dd
+				}
+			}
+			if assign[i] != best {
+				assign[i] = best
+				changed = true
+			}
+		}
+
+		for j := range clusters {
+			clusters[j] = extractPoint{}
+		}
+		for i, p := range points {
+			c := &clusters[assign[i]]
+			for n := range c.v {
+				c.v[n] += p.v[n] * p.w
+			}
+			c.w += p.w
+		}
+		for j := range clusters {
+			if clusters[j].w == 0 {
+				continue // пустой кластер сохраняет прежний центр
+			}
+			for n := range clusters[j].v {
+				clusters[j].v[n] /= clusters[j].w
+			}
+			centers[j] = clusters[j].v
+		}
+		if !changed {
+			break
+		}
+	}
+
+	var result []extractPoint
+	for _, c := range clusters {
+		if c.w > 0 {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// ---------- Median cut ----------
+
+// medianCut делит облако точек на k частей: каждый раз разрезается часть
+// с наибольшим размахом по одной из осей - по взвешенной медиане вдоль этой оси.
+func medianCut(points []extractPoint, k int) []extractPoint {
+	boxes := [][]extractPoint{append([]extractPoint(nil), points...)}
+	for len(boxes) < k {
+		best, bestAxis, bestRange := -1, 0, 0.0
+		for i, box := range boxes {
+			if len(box) < 2 {
+				continue
+			}
+			axis, r := widestAxis(box)
+			if r > bestRange {
+				best, bestAxis, bestRange = i, axis, r
+			}
+		}
+		if best < 0 {
+			break // делить больше нечего
+		}
+
+		box := boxes[best]
+		sort.Slice(box, func(i, j int) bool { return box[i].v[bestAxis] < box[j].v[bestAxis] })
+		total := 0.0
+		for _, p := range box {
+			total += p.w
+		}
+		cut, acc := 1, 0.0
+		for i, p := range box[:len(box)-1] {
+			acc += p.w
+			cut = i + 1
+			if acc >= total/2 {
+				break
+			}
+		}
+		boxes[best] = box[:cut]
+		boxes = append(boxes, box[cut:])
+	}
+
+	result := make([]extractPoint, len(boxes))
+	for i, box := range boxes {
+		result[i] = weightedMean(box)
+	}
+	return result
+}
+
+// widestAxis - ось с наибольшим размахом координат
+func widestAxis(box []extractPoint) (axis int, r float64) {
+	for a := 0; a < 3; a++ {
+		lo, hi := math.Inf(1), math.Inf(-1)
+		for _, p := range box {
+			lo = math.Min(lo, p.v[a])
+			hi = math.Max(hi, p.v[a])
+		}
+		if hi-lo > r {
+			axis, r = a, hi-lo
+		}
+	}
+	return axis, r
+}
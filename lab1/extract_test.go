@@ -0,0 +1,75 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestExtractColors(t *testing.T) {
+	// 60% красного, 30% синего, 10% белого; на красном - слабый шум
+	img := image.NewNRGBA(image.Rect(0, 0, 100, 100))
+	for y := 0; y < 100; y++ {
+		for x := 0; x < 100; x++ {
+			switch {
+			case y < 60:
+				img.Set(x, y, color.NRGBA{R: uint8(200 + (x+y)%5), G: 30, B: 30, A: 255})
+			case y < 90:
+				img.Set(x, y, color.NRGBA{R: 20, G: 40, B: 180, A: 255})
+			default:
+				img.Set(x, y, color.NRGBA{R: 255, G: 255, B: 255, A: 255})
+			}
+		}
+	}
+	// полностью прозрачный угол не учитывается
+	for x := 0; x < 10; x++ {
+		img.Set(x, 99, color.NRGBA{})
+	}
+
+	want := []struct {
+		pixels float64
+		rgb    RGBModel
+	}{
+		{6000, RGBModel{R: 202, G: 30, B: 30}},
+		{3000, RGBModel{R: 20, G: 40, B: 180}},
+		{990, RGBModel{R: 255, G: 255, B: 255}},
+	}
+	for _, method := range []string{"kmeans", "mediancut"} {
+		for _, space := range []string{"srgb", "linear", "lab", "oklab"} {
+			resp, err := extractColors(img, 3, method, space)
+			if err != nil {
+				t.Fatalf("%s/%s: %v", method, space, err)
+			}
+			if resp.Pixels != 9990 || len(resp.Colors) != 3 {
+				t.Fatalf("%s/%s: pixels %d, colors %d", method, space, resp.Pixels, len(resp.Colors))
+			}
+			for i, w := range want {
+				got := resp.Colors[i]
+				if share := roundFloat(w.pixels/9990, 4); got.Share != share {
+					t.Errorf("%s/%s: colors[%d] share %v, want %v", method, space, i, got.Share, share)
+				}
+				if rgbDiff(got.Color.RGB, w.rgb) > 1 {
+					t.Errorf("%s/%s: colors[%d] = %v, want %v", method, space, i, got.Color.RGB, w.rgb)
+				}
+			}
+		}
+	}
+
+	if _, err := extractColors(img, 0, "", ""); err == nil {
+		t.Error("k = 0: expected an error")
+	}
+	if _, err := extractColors(image.NewNRGBA(image.Rect(0, 0, 2, 2)), 3, "", ""); err == nil {
+		t.Error("transparent image: expected an error")
+	}
+}
+
+func rgbDiff(a, b RGBModel) int {
+	d := 0
+	for _, v := range []int{a.R - b.R, a.G - b.G, a.B - b.B} {
+		if v < 0 {
+			v = -v
+		}
+		d = max(d, v)
+	}
+	return d
+}
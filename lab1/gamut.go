@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/LordVillain/BSUComputerGraphicsLabs/lab1/colors"
+)
+
+// GamutInfo - попал ли запрошенный цвет в охват sRGB и что с ним пришлось сделать
+type GamutInfo struct {
+	InGamut  bool               `json:"in_gamut"`
+	Strategy string             `json:"strategy"`             // "clip", "chroma", "deltae"
+	Input    map[string]float64 `json:"input_clip,omitempty"` // на сколько обрезаны входные значения модели (по каналам)
+	Clip     RGBClip            `json:"clip"`                 // запрошенный RGB минус итоговый (единицы 0..255)
+	DeltaE   float64            `json:"delta_e"`              // ΔE*76 между запрошенным и итоговым цветом
+}
+
+// RGBClip - отклонение по каналам RGB; может быть дробным и отрицательным
+type RGBClip struct {
+	R float64 `json:"r"`
+	G float64 `json:"g"`
+	B float64 `json:"b"`
+}
+
+// mapToGamut приводит цвет (возможно, вне охвата) в охват sRGB
+// выбранной стратегией и возвращает итоговый цвет вместе с отчетом.
+func mapToGamut(c colors.Color, strategy string) (mapped colors.RGB, info GamutInfo, err error) {
+	if strategy == "" {
+		strategy = "clip"
+	}
+	info.Strategy = strategy
+	info.InGamut = colors.InGamut(c)
+
+	switch strategy {
+	case "clip":
+		mapped = colors.ClipToGamut(c)
+	case "chroma":
+		mapped = colors.ReduceChroma(c)
+	case "deltae":
+		mapped = colors.NearestByDeltaE(c)
+	default:
+		return mapped, info, fmt.Errorf("gamut must be one of: clip, chroma, deltae")
+	}
+
+	requested := c.RGB()
+	info.Clip = RGBClip{
+		R: roundFloat(requested.R-mapped.R, 2),
+		G: roundFloat(requested.G-mapped.G, 2),
+		B: roundFloat(requested.B-mapped.B, 2),
+	}
+	info.DeltaE = roundFloat(colors.DeltaE76(colors.ToLab(requested), colors.ToLab(mapped)), 2)
+	return mapped, info, nil
+}
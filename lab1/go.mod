@@ -0,0 +1,3 @@
+module github.com/LordVillain/BSUComputerGraphicsLabs/lab1
+
+go 1.22
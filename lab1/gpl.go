@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ---------- GIMP Palette (.gpl) ----------
+//
+// Текстовый формат:
+//
+//	GIMP Palette
+//	Name: <название палитры>
+//	Columns: <число столбцов при показе>
+//	# комментарий
+//	255   0   0	<название цвета>
+//
+// Цвета хранятся только в RGB (целые 0..255).
+
+func decodeGPL(data []byte) (SwatchFile, error) {
+	var file SwatchFile
+	sc := bufio.NewScanner(bytes.NewReader(data))
+
+	lineNo := 0
+	for sc.Scan() {
+		lineNo++
+		line := strings.TrimSpace(sc.Text())
+		if lineNo == 1 {
+			if line != "GIMP Palette" {
+				return file, errors.New(`missing "GIMP Palette" header`)
+			}
+			continue
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if line[0] < '0' || line[0] > '9' {
+			// заголовки "Name:", "Columns:" и т.п.
+			if key, value, ok := strings.Cut(line, ":"); ok && key == "Name" {
+				file.Name = strings.TrimSpace(value)
+			}
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			return file, fmt.Errorf("line %d: expected \"r g b name\"", lineNo)
+		}
+		var rgb [3]float64
+		for i := range rgb {
+			v, err := strconv.Atoi(fields[i])
+			if err != nil || v < 0 || v > 255 {
+				return file, fmt.Errorf("line %d: channel must be an integer 0..255", lineNo)
+			}
+			rgb[i] = float64(v)
+		}
+		file.Colors = append(file.Colors, SwatchColor{
+			Name:  strings.Join(fields[3:], " "),
+			Color: rgbRequest(rgb[0], rgb[1], rgb[2]),
+		})
+	}
+	if err := sc.Err(); err != nil {
+		return file, err
+	}
+	if lineNo == 0 {
+		return file, errors.New("empty file")
+	}
+	return file, nil
+}
+
+// encodeGPL записывает палитру в RGB (model не используется)
+func encodeGPL(name string, entries []swatchEntry, model string) ([]byte, error) {
+	var out bytes.Buffer
+	out.WriteString("GIMP Palette\n")
+	if name != "" {
+		fmt.Fprintf(&out, "Name: %s\n", strings.ReplaceAll(name, "\n", " "))
+	}
+	out.WriteString("#\n")
+	for _, e := range entries {
+		rgb := e.resp.RGB
+		label := strings.ReplaceAll(e.name, "\n", " ")
+		if label == "" {
+			label = "Untitled"
+		}
+		fmt.Fprintf(&out, "%3d %3d %3d\t%s\n", rgb.R, rgb.G, rgb.B, label)
+	}
+	return out.Bytes(), nil
+}
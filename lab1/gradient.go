@@ -0,0 +1,204 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+
+	"github.com/LordVillain/BSUComputerGraphicsLabs/lab1/colors"
+)
+
+// GradientRequest - опорные цвета градиента (не меньше двух) в любых моделях ConvertRequest
+type GradientRequest struct {
+	Colors []ConvertRequest `json:"colors"`
+	Steps  int              `json:"steps"`           // общее число цветов на выходе, включая крайние (по умолчанию 10)
+	Space  string           `json:"space,omitempty"` // "srgb", "linear", "hsv", "oklab" (по умолчанию), "oklch"
+}
+
+type GradientResponse struct {
+	Space string            `json:"space"`
+	Stops []ConvertResponse `json:"stops"`
+}
+
+// Максимальное число цветов в градиенте
+const maxGradientSteps = 1024
+
+func gradientHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req GradientRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid json: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := buildGradient(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// gradientPoint - опорный цвет в координатах выбранного пространства
+type gradientPoint struct {
+	v     [3]float64
+	alpha float64
+}
+
+func buildGradient(req GradientRequest) (GradientResponse, error) {
+	if len(req.Colors) < 2 {
+		return GradientResponse{}, errors.New("at least 2 colors are required")
+	}
+	if req.Steps == 0 {
+		req.Steps = 10
+	}
+	if req.Steps < 2 || req.Steps > maxGradientSteps {
+		return GradientResponse{}, fmt.Errorf("steps must be in 2..%d", maxGradientSteps)
+	}
+	if req.Space == "" {
+		req.Space = "oklab"
+	}
+
+	points := make([]gradientPoint, len(req.Colors))
+	for i, c := range req.Colors {
+		p, err := gradientCoords(c, req.Space)
+		if err != nil {
+			return GradientResponse{}, fmt.Errorf("colors[%d]: %v", i, err)
+		}
+		points[i] = p
+	}
+
+	// угловая координата (тон) для цилиндрических пространств
+	hueIndex := -1
+	switch req.Space {
+	case "hsv":
+		hueIndex = 0
+		fixPowerlessHues(points, 0, 1, 1e-6)
+	case "oklch":
+		hueIndex = 2
+		fixPowerlessHues(points, 2, 1, 1e-4)
+	}
+
+	// общие параметры (охват, профиль CMYK, фон) берутся у первого опорного цвета
+	first := req.Colors[0]
+	resp := GradientResponse{Space: req.Space}
+	segments := len(points) - 1
+	for i := 0; i < req.Steps; i++ {
+		t := float64(i) / float64(req.Steps-1) * float64(segments)
+		seg := int(math.Min(math.Floor(t), float64(segments-1)))
+		local := t - float64(seg)
+		a, b := points[seg], points[seg+1]
+
+		var v [3]float64
+		for k := 0; k < 3; k++ {
+			if k == hueIndex {
+				v[k] = lerpHue(a.v[k], b.v[k], local)
+			} else {
+				v[k] = a.v[k] + (b.v[k]-a.v[k])*local
+			}
+		}
+		alpha := a.alpha + (b.alpha-a.alpha)*local
+
+		stopReq := gradientRequest(req.Space, v)
+		stopReq.Gamut = first.Gamut
+		stopReq.Profile = first.Profile
+		stopReq.Background = first.Background
+		stopReq.Alpha = &alpha
+		stop, err := convertColor(stopReq)
+		if err != nil {
+			return GradientResponse{}, err
+		}
+		resp.Stops = append(resp.Stops, stop)
+	}
+	return resp, nil
+}
+
+// gradientCoords переводит опорный цвет в координаты пространства интерполяции
+func gradientCoords(c ConvertRequest, space string) (gradientPoint, error) {
+	resp, err := convertColor(c)
+	if err != nil {
+		return gradientPoint{}, err
+	}
+	p := gradientPoint{alpha: resp.Alpha}
+
+	// для всех пространств, кроме HSV, берем запрошенный цвет до приведения в охват
+	color, err := requestColor(c)
+	if err != nil {
+		return gradientPoint{}, err
+	}
+
+	switch space {
+	case "srgb":
+		rgb := color.RGB()
+		p.v = [3]float64{rgb.R, rgb.G, rgb.B}
+	case "linear":
+		rgb := color.RGB()
+		p.v = [3]float64{colors.SRGBToLinear(rgb.R / 255), colors.SRGBToLinear(rgb.G / 255), colors.SRGBToLinear(rgb.B / 255)}
+	case "hsv":
+		p.v = [3]float64{resp.HSV.H, resp.HSV.S, resp.HSV.V}
+	case "oklab":
+		lab := colors.ToOKLab(color)
+		p.v = [3]float64{lab.L, lab.A, lab.B}
+	case "oklch":
+		lch := colors.ToOKLCh(color)
+		p.v = [3]float64{lch.L, lch.C, lch.H}
+	default:
+		return gradientPoint{}, errors.New("space must be one of: srgb, linear, hsv, oklab, oklch")
+	}
+	return p, nil
+}
+
+// gradientRequest - промежуточный цвет как ConvertRequest в модели пространства интерполяции
+func gradientRequest(space string, v [3]float64) ConvertRequest {
+	switch space {
+	case "linear":
+		return rgbRequest(colors.LinearToSRGB(v[0])*255, colors.LinearToSRGB(v[1])*255, colors.LinearToSRGB(v[2])*255)
+	case "hsv":
+		return ConvertRequest{Model: "hsv", Values: map[string]float64{"h": v[0], "s": v[1], "v": v[2]}}
+	case "oklab":
+		return ConvertRequest{Model: "oklab", Values: map[string]float64{"l": v[0], "a": v[1], "b": v[2]}}
+	case "oklch":
+		return ConvertRequest{Model: "oklch", Values: map[string]float64{"l": v[0], "c": v[1], "h": v[2]}}
+	}
+	return rgbRequest(v[0], v[1], v[2])
+}
+
+// fixPowerlessHues: у ахроматических цветов тон не определен, поэтому,
+// как в CSS Color 4, берем тон соседнего опорного цвета, чтобы градиент к серому
+// не проходил через посторонние оттенки.
+func fixPowerlessHues(points []gradientPoint, hue, chroma int, eps float64) {
+	for i := range points {
+		if points[i].v[chroma] > eps {
+			continue
+		}
+		for d := 1; d < len(points); d++ {
+			if j := i - d; j >= 0 && points[j].v[chroma] > eps {
+				points[i].v[hue] = points[j].v[hue]
+				break
+			}
+			if j := i + d; j < len(points) && points[j].v[chroma] > eps {
+				points[i].v[hue] = points[j].v[hue]
+				break
+			}
+		}
+	}
+}
+
+// lerpHue - интерполяция угла по кратчайшей дуге
+func lerpHue(a, b, t float64) float64 {
+	d := math.Mod(b-a, 360)
+	if d > 180 {
+		d -= 360
+	} else if d < -180 {
+		d += 360
+	}
+	return colors.WrapHue(a + d*t)
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"errors"
+	"math"
+
+	"github.com/LordVillain/BSUComputerGraphicsLabs/lab1/colors"
+)
+
+// DeepRGBModel - итоговый sRGB в целых кодовых значениях разрядности bit_depth (0..2^bits-1)
+type DeepRGBModel struct {
+	Bits int `json:"bits"`
+	R    int `json:"r"`
+	G    int `json:"g"`
+	B    int `json:"b"`
+}
+
+// bitDepthMax - наибольшее кодовое значение для разрядности bit_depth запроса (по умолчанию 8 бит)
+func bitDepthMax(bits int) (float64, error) {
+	switch bits {
+	case 0:
+		return 255, nil
+	case 8, 10, 12, 16:
+		return float64(int(1)<<bits - 1), nil
+	}
+	return 0, errors.New("bit_depth must be one of: 8, 10, 12, 16")
+}
+
+// deepRGBModel - цвет в кодовых значениях разрядности bits (nil, если bit_depth не задан)
+func deepRGBModel(c colors.RGB, bits int) (*DeepRGBModel, error) {
+	if bits == 0 {
+		return nil, nil
+	}
+	maxCode, err := bitDepthMax(bits)
+	if err != nil {
+		return nil, err
+	}
+	code := func(v float64) int { return int(math.Round(clampFloat(v, 0, 255) / 255 * maxCode)) }
+	return &DeepRGBModel{Bits: bits, R: code(c.R), G: code(c.G), B: code(c.B)}, nil
+}
+
+// resolveHDRColor - цвет из значений моделей linear, pq и hlg.
+// Первичные цвета - rgb_space запроса; по умолчанию sRGB для linear и Rec.2020 для pq и hlg.
+func resolveHDRColor(req ConvertRequest, inputClip map[string]float64) (colors.Color, error) {
+	rf, okR := req.Values["r"]
+	gf, okG := req.Values["g"]
+	bf, okB := req.Values["b"]
+	if !okR || !okG || !okB {
+		return nil, errors.New(req.Model + " requires r,g,b")
+	}
+	var space *colors.RGBSpace
+	if req.RGBSpace != "" {
+		var err error
+		if space, err = parseRGBSpace(req.RGBSpace); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.Model == "linear" {
+		// значения больше 1 допустимы (ярче белого SDR) - за охват sRGB они выходят как обычно
+		return colors.LinearRGB{R: rf, G: gf, B: bf, Space: space}, nil
+	}
+
+	// сигналы PQ и HLG определены только на 0..1: за пределами кривые не имеют смысла
+	// (обратная EOTF PQ выше 1 дает NaN), поэтому обрезаем их и отмечаем в input_clip
+	addInputClip(inputClip, "r", rf, 0, 1)
+	addInputClip(inputClip, "g", gf, 0, 1)
+	addInputClip(inputClip, "b", bf, 0, 1)
+	rf, gf, bf = clampFloat(rf, 0, 1), clampFloat(gf, 0, 1), clampFloat(bf, 0, 1)
+	if req.Model == "pq" {
+		return colors.PQ{R: rf, G: gf, B: bf, Space: space}, nil
+	}
+	return colors.HLG{R: rf, G: gf, B: bf, Space: space}, nil
+}
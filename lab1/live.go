@@ -0,0 +1,174 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ---------- Живая конвертация через WebSocket (/api/live) ----------
+//
+// Клиент держит одно соединение и шлет сообщения LiveMessage: request заменяет
+// текущий запрос целиком, values обновляет отдельные значения текущей модели
+// (движение ползунка). Сервер отвечает не чаще раза в liveFrameInterval и считает только
+// самое свежее состояние: обновления, пришедшие между ответами, сливаются в один ответ
+// на последнее. Каждый ответ - LiveFrame с номером seq сообщения, на которое он отвечает.
+
+// LiveMessage - сообщение клиента
+type LiveMessage struct {
+	Seq     int64              `json:"seq"`
+	Request *ConvertRequest    `json:"request,omitempty"` // новый запрос целиком
+	Values  map[string]float64 `json:"values,omitempty"`  // изменение значений текущего запроса
+}
+
+// LiveFrame - ответ сервера: поля ConvertResponse или текст ошибки
+type LiveFrame struct {
+	Seq int64 `json:"seq"`
+	*ConvertResponse
+	Error string `json:"error,omitempty"`
+}
+
+// Минимальный интервал между ответами одного соединения (около 30 кадров в секунду)
+const liveFrameInterval = 30 * time.Millisecond
+
+// liveSession - состояние одного соединения
+type liveSession struct {
+	mu      sync.Mutex
+	state   *ConvertRequest // текущий запрос с учетом всех обновлений
+	pending *ConvertRequest // копия состояния, ожидающая конвертации (nil - нечего считать)
+	seq     int64
+	wake    chan struct{}
+}
+
+func liveHandler(w http.ResponseWriter, r *http.Request) {
+	ws, err := wsAccept(w, r)
+	if err != nil {
+		return
+	}
+	s := &liveSession{wake: make(chan struct{}, 1)}
+	done := make(chan struct{})
+	go s.respond(ws, done)
+
+	defer close(done)
+	for {
+		opcode, data, err := ws.ReadMessage()
+		if err != nil {
+			ws.Close(wsCloseCode(err), "")
+			return
+		}
+		if opcode != wsOpText {
+			ws.Close(wsCloseInvalidData, "text messages only")
+			return
+		}
+		var msg LiveMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			s.reject(ws, msg.Seq, "invalid json: "+err.Error())
+			continue
+		}
+		if errText := s.update(msg); errText != "" {
+			s.reject(ws, msg.Seq, errText)
+		}
+	}
+}
+
+// update применяет сообщение к состоянию и будит горутину ответа.
+// Возвращает текст ошибки, если сообщение применить нельзя.
+func (s *liveSession) update(msg LiveMessage) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch {
+	case msg.Request != nil:
+		s.state = msg.Request
+	case msg.Values != nil:
+		if s.state == nil || s.state.CSS != "" {
+			return "values update requires a previous request with model and values"
+		}
+		if s.state.Values == nil {
+			s.state.Values = map[string]float64{}
+		}
+		for k, v := range msg.Values {
+			s.state.Values[k] = v
+		}
+	default:
+		return "message must contain request or values"
+	}
+
+	// конвертация идет в другой горутине - отдаем ей копию, а не общее состояние
+	pending := *s.state
+	pending.Values = make(map[string]float64, len(s.state.Values))
+	for k, v := range s.state.Values {
+		pending.Values[k] = v
+	}
+	s.pending, s.seq = &pending, msg.Seq
+
+	select {
+	case s.wake <- struct{}{}:
+	default: // горутина уже разбужена и заберет новое состояние
+	}
+	return ""
+}
+
+// respond считает последнее состояние после каждого пробуждения и пингует клиента в простое.
+// Если предыдущий ответ был недавно, ждет до конца интервала, собирая обновления.
+func (s *liveSession) respond(ws *wsConn, done <-chan struct{}) {
+	ping := time.NewTicker(wsPingEvery)
+	defer ping.Stop()
+	var lastSent time.Time
+	for {
+		select {
+		case <-done:
+			return
+		case <-ping.C:
+			if ws.Ping() != nil {
+				return
+			}
+		case <-s.wake:
+			if wait := liveFrameInterval - time.Since(lastSent); wait > 0 {
+				timer := time.NewTimer(wait)
+				select {
+				case <-done:
+					timer.Stop()
+					return
+				case <-timer.C:
+				}
+			}
+
+			s.mu.Lock()
+			req, seq := s.pending, s.seq
+			s.pending = nil
+			s.mu.Unlock()
+			if req == nil {
+				continue
+			}
+
+			frame := LiveFrame{Seq: seq}
+			if resp, err := convertColor(*req); err != nil {
+				frame.Error = err.Error()
+			} else {
+				frame.ConvertResponse = &resp
+			}
+			if s.send(ws, frame) != nil {
+				return
+			}
+			lastSent = time.Now()
+		}
+	}
+}
+
+// reject отвечает на сообщение ошибкой, не трогая текущее состояние
+func (s *liveSession) reject(ws *wsConn, seq int64, errText string) {
+	s.send(ws, LiveFrame{Seq: seq, Error: errText})
+}
+
+// send отправляет кадр. Результат, который нельзя записать в JSON (NaN, Inf), -
+// ошибка этого сообщения, а не соединения: клиент получает кадр с ошибкой и сессия остается открытой.
+func (s *liveSession) send(ws *wsConn, frame LiveFrame) error {
+	data, err := json.Marshal(frame)
+	if err != nil {
+		log.Printf("live: seq %d: %v", frame.Seq, err)
+		data, _ = json.Marshal(LiveFrame{Seq: frame.Seq, Error: "result cannot be encoded: " + err.Error()})
+	}
+	return ws.WriteText(data)
+}
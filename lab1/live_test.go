@@ -0,0 +1,215 @@
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// wsTestClient - клиент WebSocket для тестов: рукопожатие и маскированные кадры
+type wsTestClient struct {
+	conn net.Conn
+	br   *bufio.Reader
+}
+
+func dialWS(t *testing.T, srv *httptest.Server, origin string) *wsTestClient {
+	t.Helper()
+	conn, err := net.Dial("tcp", srv.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	req := "GET /api/live HTTP/1.1\r\nHost: " + srv.Listener.Addr().String() + "\r\n" +
+		"Upgrade: websocket\r\nConnection: keep-alive, Upgrade\r\n" +
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\nSec-WebSocket-Version: 13\r\n"
+	if origin != "" {
+		req += "Origin: " + origin + "\r\n"
+	}
+	conn.Write([]byte(req + "\r\n"))
+
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		conn.Close()
+		return &wsTestClient{br: bufio.NewReader(strings.NewReader(resp.Status))}
+	}
+	// пример ключа и ответа из RFC 6455, раздел 1.3
+	if got := resp.Header.Get("Sec-WebSocket-Accept"); got != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Fatalf("Sec-WebSocket-Accept = %q", got)
+	}
+	return &wsTestClient{conn: conn, br: br}
+}
+
+func (c *wsTestClient) send(t *testing.T, opcode byte, payload []byte) {
+	t.Helper()
+	mask := [4]byte{1, 2, 3, 4}
+	frame := []byte{0x80 | opcode}
+	switch n := len(payload); {
+	case n <= 125:
+		frame = append(frame, 0x80|byte(n))
+	default:
+		frame = append(frame, 0x80|126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(n))
+	}
+	frame = append(frame, mask[:]...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+	if _, err := c.conn.Write(frame); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (c *wsTestClient) sendJSON(t *testing.T, v any) {
+	t.Helper()
+	data, _ := json.Marshal(v)
+	c.send(t, wsOpText, data)
+}
+
+// read возвращает следующий кадр сервера (сервер не маскирует и не фрагментирует)
+func (c *wsTestClient) read(t *testing.T) (byte, []byte) {
+	t.Helper()
+	var head [2]byte
+	if _, err := io.ReadFull(c.br, head[:]); err != nil {
+		t.Fatal(err)
+	}
+	n := int(head[1] & 0x7F)
+	switch n {
+	case 126:
+		var ext [2]byte
+		io.ReadFull(c.br, ext[:])
+		n = int(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		io.ReadFull(c.br, ext[:])
+		n = int(binary.BigEndian.Uint64(ext[:]))
+	}
+	payload := make([]byte, n)
+	if _, err := io.ReadFull(c.br, payload); err != nil {
+		t.Fatal(err)
+	}
+	return head[0] & 0x0F, payload
+}
+
+func (c *wsTestClient) frame(t *testing.T) LiveFrame {
+	t.Helper()
+	for {
+		op, data := c.read(t)
+		if op != wsOpText {
+			continue
+		}
+		var f LiveFrame
+		if err := json.Unmarshal(data, &f); err != nil {
+			t.Fatal(err)
+		}
+		return f
+	}
+}
+
+func TestLiveConvert(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(liveHandler))
+	defer srv.Close()
+	c := dialWS(t, srv, "http://"+srv.Listener.Addr().String())
+	defer c.conn.Close()
+
+	c.sendJSON(t, LiveMessage{Seq: 1, Request: &ConvertRequest{Model: "rgb", Values: map[string]float64{"r": 255, "g": 0, "b": 0}}})
+	if f := c.frame(t); f.Seq != 1 || f.ConvertResponse == nil || f.RGB != (RGBModel{R: 255}) {
+		t.Fatalf("frame 1 = %+v", f)
+	}
+
+	// поток обновлений ползунка: ответы приходят по порядку и сливаются,
+	// последний - на последнее значение
+	for i := 2; i <= 50; i++ {
+		c.sendJSON(t, LiveMessage{Seq: int64(i), Values: map[string]float64{"g": float64(i)}})
+	}
+	last, frames := LiveFrame{}, 0
+	for last.Seq != 50 {
+		f := c.frame(t)
+		if f.Seq <= last.Seq {
+			t.Fatalf("frame %d after %d", f.Seq, last.Seq)
+		}
+		last = f
+		frames++
+	}
+	if frames >= 49 {
+		t.Errorf("%d frames for 49 updates: nothing merged", frames)
+	}
+	if last.RGB != (RGBModel{R: 255, G: 50}) {
+		t.Errorf("last frame rgb = %v", last.RGB)
+	}
+
+	// ошибка конвертации не рвет соединение
+	c.sendJSON(t, LiveMessage{Seq: 52, Request: &ConvertRequest{Model: "plasma"}})
+	if f := c.frame(t); f.Seq != 52 || f.Error == "" {
+		t.Errorf("frame for unknown model = %+v", f)
+	}
+	// как и значение, дающее нечисловой результат
+	c.sendJSON(t, LiveMessage{Seq: 53, Request: &ConvertRequest{Model: "rgb", Values: map[string]float64{"r": 1e308, "g": 0, "b": 0}}})
+	if f := c.frame(t); f.Seq != 53 || f.Error == "" {
+		t.Errorf("frame for r=1e308 = %+v", f)
+	}
+	c.sendJSON(t, LiveMessage{Seq: 54, Request: &ConvertRequest{CSS: "blue"}})
+	if f := c.frame(t); f.Seq != 54 || f.ConvertResponse == nil || f.RGB != (RGBModel{B: 255}) {
+		t.Errorf("frame after bad value = %+v", f)
+	}
+
+	// ping - pong с той же нагрузкой, close - ответный close
+	c.send(t, wsOpPing, []byte("hi"))
+	if op, data := c.read(t); op != wsOpPong || string(data) != "hi" {
+		t.Errorf("ping answer: opcode %d %q", op, data)
+	}
+	c.send(t, wsOpClose, binary.BigEndian.AppendUint16(nil, wsCloseNormal))
+	if op, _ := c.read(t); op != wsOpClose {
+		t.Errorf("close answer: opcode %d", op)
+	}
+}
+
+func TestLiveCloseCodes(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(liveHandler))
+	defer srv.Close()
+
+	for _, tc := range []struct {
+		name  string
+		frame []byte
+		code  uint16
+	}{
+		{"unmasked frame", []byte{0x80 | wsOpText, 2, '{', '}'}, wsCloseProtocol},
+		{"too big", append([]byte{0x80 | wsOpText, 0x80 | 127}, binary.BigEndian.AppendUint64(nil, wsMaxMessage+1)...), wsCloseTooBig},
+	} {
+		c := dialWS(t, srv, "")
+		c.conn.Write(tc.frame)
+		op, data := c.read(t)
+		if op != wsOpClose || len(data) < 2 || binary.BigEndian.Uint16(data) != tc.code {
+			t.Errorf("%s: opcode %d, payload %q, want close %d", tc.name, op, data, tc.code)
+		}
+		c.conn.Close()
+	}
+}
+
+func TestLiveRejectsCrossOrigin(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(liveHandler))
+	defer srv.Close()
+	if c := dialWS(t, srv, "http://evil.example"); c.conn != nil {
+		c.conn.Close()
+		t.Error("cross-origin connection accepted")
+	}
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUpgradeRequired {
+		t.Errorf("plain GET: status %d", resp.StatusCode)
+	}
+}
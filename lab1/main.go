@@ -2,46 +2,110 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"log"
 	"math"
 	"net/http"
 	"path/filepath"
 	"strconv"
+
+	"github.com/LordVillain/BSUComputerGraphicsLabs/lab1/colors"
 )
 
 type ConvertRequest struct {
-	Model  string                 `json:"model"` // "rgb", "cmyk", "hsv"
-	Values map[string]float64     `json:"values"`
+	Model  string             `json:"model"` // "rgb", "cmyk", "hsv", "hsl", "hwb", "hsi", "xyz", "lab", "oklab", "oklch", "kelvin", "ycbcr", "yuv", "yiq", "linear", "pq", "hlg"
+	Values map[string]float64 `json:"values"`
+	Gamut  string             `json:"gamut,omitempty"` // стратегия приведения в охват: "clip" (по умолчанию), "chroma", "deltae"
+	CSS    string             `json:"css,omitempty"`   // цвет строкой CSS ("#ff8800", "hsl(...)", "oklch(...)", "red"); заменяет model/values
+
+	Alpha      *float64        `json:"alpha,omitempty"`      // непрозрачность 0..1 (по умолчанию 1 или альфа из CSS)
+	Background *ConvertRequest `json:"background,omitempty"` // фон для наложения полупрозрачного цвета (по умолчанию белый)
+
+	Profile *CMYKProfileSpec `json:"profile,omitempty"` // профиль CMYK (по умолчанию наивная формула 1-K)
+
+	White       string `json:"white,omitempty"`        // белая точка значений xyz и lab: "D65" (по умолчанию), "D50", "A", ...
+	TargetWhite string `json:"target_white,omitempty"` // белая точка для блока adapted ответа
+	Adaptation  string `json:"adaptation,omitempty"`   // хроматическая адаптация: "bradford" (по умолчанию), "vonkries", "xyz"
+	CCTMethod   string `json:"cct_method,omitempty"`   // оценка цветовой температуры: "robertson" (по умолчанию), "mccamy"
+
+	Standard string `json:"standard,omitempty"` // матрица ycbcr и yuv: "bt601" (по умолчанию), "bt709", "bt2020"
+	Range    string `json:"range,omitempty"`    // диапазон ycbcr: "full" (по умолчанию), "limited"
+
+	RGBSpace string `json:"rgb_space,omitempty"` // пространство значений rgb, linear, pq, hlg: "srgb", "display-p3", "adobe-rgb", "rec2020"
+	BitDepth int    `json:"bit_depth,omitempty"` // разрядность значений rgb и блока rgb_deep: 8 (по умолчанию), 10, 12, 16
 }
 
 type ConvertResponse struct {
-	RGB  RGBModel  `json:"rgb"`
-	CMYK CMYKModel `json:"cmyk"`
-	HSV  HSVModel  `json:"hsv"`
+	RGB  RGBModel    `json:"rgb"`
+	CMYK colors.CMYK `json:"cmyk"`
+	HSV  colors.HSV  `json:"hsv"`
+	HSL  colors.HSL  `json:"hsl"`
+	HWB  colors.HWB  `json:"hwb"`
+	HSI  colors.HSI  `json:"hsi"`
+	XYZ  colors.XYZ  `json:"xyz"`
+	Lab  colors.Lab  `json:"lab"`
+
+	OKLab colors.OKLab `json:"oklab"`
+	OKLCh colors.OKLCh `json:"oklch"`
+
+	YCbCr colors.YCbCr `json:"ycbcr"` // по стандарту и диапазону запроса
+	YUV   colors.YUV   `json:"yuv"`   // по стандарту запроса
+	YIQ   colors.YIQ   `json:"yiq"`
+
+	Spaces map[string]SpaceModel `json:"spaces"` // во всех рабочих RGB-пространствах, с признаком попадания в охват
+
+	Linear  colors.LinearRGB `json:"linear"`             // линейный sRGB: 1 - белый, больше 1 - HDR
+	PQ      colors.PQ        `json:"pq"`                 // сигнал PQ, Rec.2020 (белый SDR = 203 кд/м²)
+	HLG     colors.HLG       `json:"hlg"`                // сигнал HLG, Rec.2020
+	RGBDeep *DeepRGBModel    `json:"rgb_deep,omitempty"` // итоговый RGB в разрядности bit_depth
+
+	Kelvin  *colors.Kelvin `json:"kelvin,omitempty"`  // цветовая температура, если цвет близок к линии черного тела
+	Adapted *AdaptedModel  `json:"adapted,omitempty"` // цвет относительно target_white
+
+	Alpha     float64  `json:"alpha"`     // непрозрачность 0..1
+	Flattened RGBModel `json:"flattened"` // цвет, наложенный на фон (без прозрачности)
+
+	Gamut GamutInfo  `json:"gamut"`
+	CSS   CSSStrings `json:"css"`
 }
 
+// RGBModel - итоговый цвет в целых 0..255
 type RGBModel struct {
 	R int `json:"r"`
 	G int `json:"g"`
 	B int `json:"b"`
 }
 
-type CMYKModel struct {
-	C float64 `json:"c"`
-	M float64 `json:"m"`
-	Y float64 `json:"y"`
-	K float64 `json:"k"`
+// newRGBModel округляет цвет до целых и обрезает каналы до 0..255
+func newRGBModel(c colors.RGB) RGBModel {
+	return RGBModel{
+		R: clampInt(int(math.Round(c.R)), 0, 255),
+		G: clampInt(int(math.Round(c.G)), 0, 255),
+		B: clampInt(int(math.Round(c.B)), 0, 255),
+	}
 }
 
-type HSVModel struct {
-	H float64 `json:"h"`
-	S float64 `json:"s"`
-	V float64 `json:"v"`
+func (m RGBModel) color() colors.RGB {
+	return colors.RGB{R: float64(m.R), G: float64(m.G), B: float64(m.B)}
 }
 
 func main() {
 	http.Handle("/", http.FileServer(http.Dir("static")))
 	http.HandleFunc("/api/convert", convertHandler)
+	http.HandleFunc("/api/convert/batch", batchHandler)
+	http.HandleFunc("/api/live", liveHandler)
+	http.HandleFunc("/api/delta", deltaHandler)
+	http.HandleFunc("/api/palette", paletteHandler)
+	http.HandleFunc("/api/contrast", contrastHandler)
+	http.HandleFunc("/api/simulate", simulateHandler)
+	http.HandleFunc("/api/gradient", gradientHandler)
+	http.HandleFunc("/api/mix", mixHandler)
+	http.HandleFunc("/api/names", namesHandler)
+	http.HandleFunc("/api/swatches/import", swatchImportHandler)
+	http.HandleFunc("/api/swatches/export", swatchExportHandler)
+	http.HandleFunc("/api/swatches/render", swatchSheetHandler)
+	http.HandleFunc("/api/extract", extractHandler)
+	http.HandleFunc("/api/spectral", spectralHandler)
 
 	port := 8079
 	log.Printf("Server running at http://localhost:%d/ (serving ./static)\n", port)
@@ -60,22 +124,49 @@ func convertHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// 1. Определяем базовый цвет (RGB) на основе входной модели
-	var rgb RGBModel
-	
+	resp, err := convertColor(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	data, err := json.Marshal(resp)
+	if err != nil {
+		http.Error(w, "failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(append(data, '\n'))
+}
+
+// resolveColor определяет цвет на основе входной модели запроса.
+// Цвет не обрезается (может оказаться вне охвата sRGB); отдельно возвращаются
+// обрезанные входные значения модели по каналам. profile - профиль CMYK запроса
+// (newCMYKProfile), по нему читаются значения модели cmyk.
+func resolveColor(req ConvertRequest, profile colors.CMYKProfile) (color colors.Color, inputClip map[string]float64, err error) {
+	inputClip = map[string]float64{}
+
 	switch req.Model {
 	case "rgb":
 		rf, okR := req.Values["r"]
 		gf, okG := req.Values["g"]
 		bf, okB := req.Values["b"]
 		if !okR || !okG || !okB {
-			http.Error(w, "rgb requires r,g,b", http.StatusBadRequest)
-			return
+			return nil, nil, errors.New("rgb requires r,g,b")
+		}
+		space, err := parseRGBSpace(req.RGBSpace)
+		if err != nil {
+			return nil, nil, err
 		}
-		rgb = RGBModel{
-			R: clampInt(int(math.Round(rf)), 0, 255),
-			G: clampInt(int(math.Round(gf)), 0, 255),
-			B: clampInt(int(math.Round(bf)), 0, 255),
+		// значения разрядности bit_depth приводим к шкале 0..255
+		maxCode, err := bitDepthMax(req.BitDepth)
+		if err != nil {
+			return nil, nil, err
+		}
+		rf, gf, bf = rf*255/maxCode, gf*255/maxCode, bf*255/maxCode
+		color = colors.RGB{R: rf, G: gf, B: bf}
+		if space != colors.SRGB {
+			color = colors.SpaceRGB{R: rf, G: gf, B: bf, Space: space}
 		}
 	case "cmyk":
 		cf, okC := req.Values["c"]
@@ -83,186 +174,332 @@ func convertHandler(w http.ResponseWriter, r *http.Request) {
 		yf, okY := req.Values["y"]
 		kf, okK := req.Values["k"]
 		if !okC || !okM || !okY || !okK {
-			http.Error(w, "cmyk requires c,m,y,k", http.StatusBadRequest)
-			return
-		}
-		r64, g64, b64 := CMYKToRGB(cf, mf, yf, kf)
-		rgb = RGBModel{
-			R: clampInt(int(math.Round(r64)), 0, 255),
-			G: clampInt(int(math.Round(g64)), 0, 255),
-			B: clampInt(int(math.Round(b64)), 0, 255),
+			return nil, nil, errors.New("cmyk requires c,m,y,k")
 		}
+		// CMYK за пределами 0..100% физического смысла не имеет - такие значения обрезаются
+		addInputClip(inputClip, "c", cf, 0, 100)
+		addInputClip(inputClip, "m", mf, 0, 100)
+		addInputClip(inputClip, "y", yf, 0, 100)
+		addInputClip(inputClip, "k", kf, 0, 100)
+		addInkLimitClip(inputClip, req.Profile, cf, mf, yf, kf)
+		color = colors.ProfiledCMYK{CMYK: colors.CMYK{C: cf, M: mf, Y: yf, K: kf}, Profile: profile}
 	case "hsv":
 		hf, okH := req.Values["h"]
 		sf, okS := req.Values["s"]
 		vf, okV := req.Values["v"]
 		if !okH || !okS || !okV {
-			http.Error(w, "hsv requires h,s,v", http.StatusBadRequest)
-			return
+			return nil, nil, errors.New("hsv requires h,s,v")
+		}
+		addInputClip(inputClip, "s", sf, 0, 100)
+		addInputClip(inputClip, "v", vf, 0, 100)
+		color = colors.HSV{H: hf, S: sf, V: vf}
+	case "hsl":
+		hf, okH := req.Values["h"]
+		sf, okS := req.Values["s"]
+		lf, okL := req.Values["l"]
+		if !okH || !okS || !okL {
+			return nil, nil, errors.New("hsl requires h,s,l")
+		}
+		addInputClip(inputClip, "s", sf, 0, 100)
+		addInputClip(inputClip, "l", lf, 0, 100)
+		color = colors.HSL{H: hf, S: sf, L: lf}
+	case "hwb":
+		hf, okH := req.Values["h"]
+		wf, okW := req.Values["w"]
+		bf, okB := req.Values["b"]
+		if !okH || !okW || !okB {
+			return nil, nil, errors.New("hwb requires h,w,b")
+		}
+		addInputClip(inputClip, "w", wf, 0, 100)
+		addInputClip(inputClip, "b", bf, 0, 100)
+		color = colors.HWB{H: hf, W: wf, B: bf}
+	case "hsi":
+		hf, okH := req.Values["h"]
+		sf, okS := req.Values["s"]
+		inf, okI := req.Values["i"]
+		if !okH || !okS || !okI {
+			return nil, nil, errors.New("hsi requires h,s,i")
 		}
-		r64, g64, b64 := HSVToRGB(hf, sf, vf)
-		rgb = RGBModel{
-			R: clampInt(int(math.Round(r64)), 0, 255),
-			G: clampInt(int(math.Round(g64)), 0, 255),
-			B: clampInt(int(math.Round(b64)), 0, 255),
+		addInputClip(inputClip, "s", sf, 0, 100)
+		addInputClip(inputClip, "i", inf, 0, 100)
+		color = colors.HSI{H: hf, S: sf, I: inf}
+	case "xyz":
+		xf, okX := req.Values["x"]
+		yf, okY := req.Values["y"]
+		zf, okZ := req.Values["z"]
+		if !okX || !okY || !okZ {
+			return nil, nil, errors.New("xyz requires x,y,z")
+		}
+		color, err = adaptToD65(colors.XYZ{X: xf, Y: yf, Z: zf}, req)
+		if err != nil {
+			return nil, nil, err
+		}
+	case "lab":
+		lf, okL := req.Values["l"]
+		af, okA := req.Values["a"]
+		bf, okB := req.Values["b"]
+		if !okL || !okA || !okB {
+			return nil, nil, errors.New("lab requires l,a,b")
+		}
+		lab := colors.Lab{L: lf, A: af, B: bf}
+		color = lab
+		if req.White != "" {
+			// Lab относительно другой белой точки: переходим в XYZ этой точки и адаптируем к D65
+			white, err := parseWhite(req.White)
+			if err != nil {
+				return nil, nil, err
+			}
+			if color, err = adaptToD65(colors.XYZFromLab(lab, white), req); err != nil {
+				return nil, nil, err
+			}
+		}
+	case "oklab":
+		lf, okL := req.Values["l"]
+		af, okA := req.Values["a"]
+		bf, okB := req.Values["b"]
+		if !okL || !okA || !okB {
+			return nil, nil, errors.New("oklab requires l,a,b")
+		}
+		color = colors.OKLab{L: lf, A: af, B: bf}
+	case "oklch":
+		lf, okL := req.Values["l"]
+		cf, okC := req.Values["c"]
+		hf, okH := req.Values["h"]
+		if !okL || !okC || !okH {
+			return nil, nil, errors.New("oklch requires l,c,h")
+		}
+		addInputClip(inputClip, "c", cf, 0, math.Inf(1))
+		color = colors.OKLCh{L: lf, C: math.Max(cf, 0), H: hf}
+	case "kelvin":
+		kf, okK := req.Values["k"]
+		if !okK {
+			return nil, nil, errors.New("kelvin requires k (duv is optional)")
+		}
+		duv := req.Values["duv"]
+		addInputClip(inputClip, "k", kf, colors.MinKelvin, colors.MaxKelvin)
+		addInputClip(inputClip, "duv", duv, -colors.MaxDuv, colors.MaxDuv)
+		color = colors.Kelvin{
+			K:   clampFloat(kf, colors.MinKelvin, colors.MaxKelvin),
+			Duv: clampFloat(duv, -colors.MaxDuv, colors.MaxDuv),
+		}
+	case "ycbcr", "yuv", "yiq":
+		if color, err = resolveVideoColor(req, inputClip); err != nil {
+			return nil, nil, err
+		}
+	case "linear", "pq", "hlg":
+		if color, err = resolveHDRColor(req, inputClip); err != nil {
+			return nil, nil, err
 		}
 	default:
-		http.Error(w, "model must be one of: rgb, cmyk, hsv", http.StatusBadRequest)
-		return
+		return nil, nil, errors.New("model must be one of: rgb, cmyk, hsv, hsl, hwb, hsi, xyz, lab, oklab, oklch, kelvin, ycbcr, yuv, yiq, linear, pq, hlg")
+	}
+	if req.White != "" && req.Model != "xyz" && req.Model != "lab" {
+		return nil, nil, errors.New("white applies only to xyz and lab models")
 	}
 
-	// 2. Рассчитываем значения для всех моделей из полученного RGB
-	c, m, y, k := RGBToCMYK(rgb.R, rgb.G, rgb.B)
-	h, s, v := RGBToHSV(rgb.R, rgb.G, rgb.B)
+	return color, inputClip, nil
+}
 
-	// Формируем структуры ответа с расчетными данными
-	respCMYK := CMYKModel{
-		C: roundFloat(c*100, 2),
-		M: roundFloat(m*100, 2),
-		Y: roundFloat(y*100, 2),
-		K: roundFloat(k*100, 2),
+// expandCSS заменяет CSS-строку запроса эквивалентными model/values.
+// Альфа из CSS используется, если она не задана в запросе явно.
+func expandCSS(req ConvertRequest) (ConvertRequest, error) {
+	if req.CSS == "" {
+		return req, nil
 	}
-	respHSV := HSVModel{
-		H: roundFloat(h, 2),
-		S: roundFloat(s*100, 2),
-		V: roundFloat(v*100, 2),
+	parsed, alpha, err := parseCSSColor(req.CSS)
+	if err != nil {
+		return req, err
 	}
+	req.Model, req.Values = parsed.Model, parsed.Values
+	if req.Alpha == nil {
+		req.Alpha = &alpha
+	}
+	return req, nil
+}
 
-	// 3. ВАЖНО: Перезаписываем значения для текущей активной модели теми, что ввел пользователь.
-	// Это предотвращает сброс ползунков из-за математических округлений или особенностей моделей
-	// (например, при K=100 в CMYK значения C,M,Y математически не важны, но интерфейс их терять не должен).
-	if req.Model == "cmyk" {
-		respCMYK.C = req.Values["c"]
-		respCMYK.M = req.Values["m"]
-		respCMYK.Y = req.Values["y"]
-		respCMYK.K = req.Values["k"]
-	} else if req.Model == "hsv" {
-		respHSV.H = req.Values["h"]
-		respHSV.S = req.Values["s"]
-		respHSV.V = req.Values["v"]
+// requestColor - запрошенный цвет до приведения в охват (CSS раскрывается, профиль CMYK создается)
+func requestColor(req ConvertRequest) (colors.Color, error) {
+	req, err := expandCSS(req)
+	if err != nil {
+		return nil, err
+	}
+	profile, err := newCMYKProfile(req.Profile)
+	if err != nil {
+		return nil, err
 	}
-	// Для RGB обычно полезнее оставить clamp-значения (0-255), поэтому их не перезаписываем.
+	color, _, err := resolveColor(req, profile)
+	return color, err
+}
 
-	resp := ConvertResponse{
-		RGB:  rgb,
-		CMYK: respCMYK,
-		HSV:  respHSV,
+// convertColor рассчитывает представление цвета запроса во всех моделях
+func convertColor(req ConvertRequest) (ConvertResponse, error) {
+	req, err := expandCSS(req)
+	if err != nil {
+		return ConvertResponse{}, err
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(resp)
-}
+	// Профиль CMYK нужен и для входных значений cmyk, и для ответа
+	profile, err := newCMYKProfile(req.Profile)
+	if err != nil {
+		return ConvertResponse{}, err
+	}
 
-// ---------- Конвертации ----------
+	// 1. Определяем цвет на основе входной модели
+	color, inputClip, err := resolveColor(req, profile)
+	if err != nil {
+		return ConvertResponse{}, err
+	}
 
-// CMYK (0..100%) -> RGB (0..255)
-func CMYKToRGB(cPct, mPct, yPct, kPct float64) (r, g, b float64) {
-	c := clampFloat(cPct/100.0, 0, 1)
-	m := clampFloat(mPct/100.0, 0, 1)
-	y := clampFloat(yPct/100.0, 0, 1)
-	k := clampFloat(kPct/100.0, 0, 1)
+	// Приводим цвет в охват sRGB выбранной стратегией и запоминаем, насколько он изменился
+	mapped, gamut, err := mapToGamut(color, req.Gamut)
+	if err != nil {
+		return ConvertResponse{}, err
+	}
+	exact := gamut.InGamut && len(inputClip) == 0
 
-	// стандартый подход: r = 255*(1-c)*(1-k)
-	r = (1.0-c)*(1.0-k) * 255.0
-	g = (1.0-m)*(1.0-k) * 255.0
-	b = (1.0-y)*(1.0-k) * 255.0
-	return
-}
+	alpha := 1.0
+	if req.Alpha != nil {
+		alpha = *req.Alpha
+		addInputClip(inputClip, "alpha", alpha, 0, 1)
+		alpha = clampFloat(alpha, 0, 1)
+	}
 
-// RGB 0..255 -> CMYK 0..1
-func RGBToCMYK(rInt, gInt, bInt int) (c, m, y, k float64) {
-	r := clampFloat(float64(rInt)/255.0, 0, 1)
-	g := clampFloat(float64(gInt)/255.0, 0, 1)
-	b := clampFloat(float64(bInt)/255.0, 0, 1)
-
-	k = 1 - math.Max(r, math.Max(g, b))
-	if almostEqual(k, 1.0) {
-		// черный
-		return 0, 0, 0, 1
-	}
-	c = (1 - r - k) / (1 - k)
-	m = (1 - g - k) / (1 - k)
-	y = (1 - b - k) / (1 - k)
-	// защита от негативных нулей
-	c = clampFloat(c, 0, 1)
-	m = clampFloat(m, 0, 1)
-	y = clampFloat(y, 0, 1)
-	return
-}
+	if len(inputClip) > 0 {
+		gamut.InGamut = false
+		gamut.Input = inputClip
+	}
 
-// RGB 0..255 -> HSV: H 0..360, S 0..1, V 0..1
-func RGBToHSV(rInt, gInt, bInt int) (h, s, v float64) {
-	r := clampFloat(float64(rInt)/255.0, 0, 1)
-	g := clampFloat(float64(gInt)/255.0, 0, 1)
-	b := clampFloat(float64(bInt)/255.0, 0, 1)
-
-	max := math.Max(r, math.Max(g, b))
-	min := math.Min(r, math.Min(g, b))
-	delta := max - min
-
-	// Hue
-	if almostEqual(delta, 0) {
-		h = 0
-	} else {
-		switch {
-		case almostEqual(max, r):
-			h = 60 * math.Mod((g-b)/delta, 6)
-		case almostEqual(max, g):
-			h = 60 * (((b-r)/delta) + 2)
-		default: // max == b
-			h = 60 * (((r-g)/delta) + 4)
-		}
-		if h < 0 {
-			h += 360
+	// Фон для наложения: задается в любой модели, его собственная прозрачность не учитывается
+	bg := colors.RGB{R: 255, G: 255, B: 255}
+	if req.Background != nil {
+		bgReq := *req.Background
+		bgReq.Background = nil
+		bgResp, err := convertColor(bgReq)
+		if err != nil {
+			return ConvertResponse{}, errors.New("background: " + err.Error())
 		}
+		bg = bgResp.RGB.color()
+	}
+	flat := colors.CompositeOver(mapped, alpha, bg)
+
+	rgb := newRGBModel(mapped)
+
+	// 2. Рассчитываем значения для всех моделей в дробных числах (без округления RGB до целых).
+	// Если цвет не пришлось менять (он в охвате и входные значения не обрезались), считаем
+	// прямо от входного цвета: для его собственной модели преобразование тождественное,
+	// поэтому значения не "уплывают" из-за округлений и не теряются там, где модель
+	// вырождается (тон серого, C,M,Y при K=100). Иначе - от приведенного в охват RGB.
+	var src colors.Color = mapped
+	if exact {
+		src = color
+	}
+
+	// Значения округляются до точности, при которой перевод обратно в RGB
+	// восстанавливает тот же 8-битный цвет (см. colors/Readme.md)
+	resp := ConvertResponse{
+		RGB:       rgb,
+		CMYK:      colors.ToCMYKProfile(src, profile).Rounded(),
+		HSV:       colors.ToHSV(src).Rounded(),
+		HSL:       colors.ToHSL(src).Rounded(),
+		HWB:       colors.ToHWB(src).Rounded(),
+		HSI:       colors.ToHSI(src).Rounded(),
+		XYZ:       colors.ToXYZ(src).Rounded(),
+		Lab:       colors.ToLab(src).Rounded(),
+		OKLab:     colors.ToOKLab(src).Rounded(),
+		OKLCh:     colors.ToOKLCh(src).Rounded(),
+		Alpha:     roundFloat(alpha, 4),
+		Flattened: newRGBModel(flat),
+		Gamut:     gamut,
+	}
+	standard, limited, err := videoEncoding(req)
+	if err != nil {
+		return ConvertResponse{}, err
+	}
+	resp.YCbCr = colors.ToYCbCr(src, standard, limited).Rounded()
+	resp.YUV = colors.ToYUV(src, standard).Rounded()
+	resp.YIQ = colors.ToYIQ(src).Rounded()
+
+	resp.Spaces = spaceModels(color)
+	// HDR-значения тоже считаются от исходного цвета: ярче белого он может быть только до приведения в охват
+	resp.Linear = colors.ToLinearRGB(color, nil).Rounded()
+	resp.PQ = colors.ToPQ(color, nil).Rounded()
+	resp.HLG = colors.ToHLG(color, nil).Rounded()
+	if resp.RGBDeep, err = deepRGBModel(mapped, req.BitDepth); err != nil {
+		return ConvertResponse{}, err
+	}
+
+	cctMethod, err := parseCCTMethod(req.CCTMethod)
+	if err != nil {
+		return ConvertResponse{}, err
+	}
+	if k, ok := colors.ToKelvin(src, cctMethod); ok {
+		k = k.Rounded()
+		resp.Kelvin = &k
+	}
+	if resp.Adapted, err = adaptedModel(src, req); err != nil {
+		return ConvertResponse{}, err
 	}
 
-	// Saturation
-	if almostEqual(max, 0) {
-		s = 0
-	} else {
-		s = delta / max
+	// Огромные конечные значения (r=1e308, a=1e200) переполняются в формулах и дают NaN или Inf,
+	// которые нельзя передать в JSON. Проверка здесь покрывает и пакетную конвертацию, смешение,
+	// листы образцов и /api/live
+	if !resp.finite() {
+		return ConvertResponse{}, errOutOfRange
 	}
 
-	v = max
-	return
+	resp.CSS = formatCSS(resp)
+	return resp, nil
 }
 
-// HSV -> RGB (inputs: H 0..360, S 0..100, V 0..100) -> RGB 0..255
-func HSVToRGB(hDeg, sPct, vPct float64) (r, g, b float64) {
-	h := math.Mod(hDeg, 360)
-	if h < 0 {
-		h += 360
-	}
-	s := clampFloat(sPct/100.0, 0, 1)
-	v := clampFloat(vPct/100.0, 0, 1)
-
-	c := v * s
-	x := c * (1 - math.Abs(math.Mod(h/60.0, 2)-1))
-	m := v - c
-
-	var rp, gp, bp float64
-	switch {
-	case 0 <= h && h < 60:
-		rp, gp, bp = c, x, 0
-	case 60 <= h && h < 120:
-		rp, gp, bp = x, c, 0
-	case 120 <= h && h < 180:
-		rp, gp, bp = 0, c, x
-	case 180 <= h && h < 240:
-		rp, gp, bp = 0, x, c
-	case 240 <= h && h < 300:
-		rp, gp, bp = x, 0, c
-	default:
-		rp, gp, bp = c, 0, x
-	}
+// errOutOfRange - результат расчета вышел за пределы чисел с плавающей точкой
+var errOutOfRange = errors.New("values are out of range: the result is not a finite number")
 
-	r = (rp + m) * 255.0
-	g = (gp + m) * 255.0
-	b = (bp + m) * 255.0
-	return
+// finiteFloats - все ли значения конечны (не NaN и не ±Inf)
+func finiteFloats(vs ...float64) bool {
+	for _, v := range vs {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return false
+		}
+	}
+	return true
 }
 
+// finite проверяет все дробные значения ответа; целые RGB получены из тех же расчетов
+func (r *ConvertResponse) finite() bool {
+	ok := finiteFloats(
+		r.CMYK.C, r.CMYK.M, r.CMYK.Y, r.CMYK.K,
+		r.HSV.H, r.HSV.S, r.HSV.V,
+		r.HSL.H, r.HSL.S, r.HSL.L,
+		r.HWB.H, r.HWB.W, r.HWB.B,
+		r.HSI.H, r.HSI.S, r.HSI.I,
+		r.XYZ.X, r.XYZ.Y, r.XYZ.Z,
+		r.Lab.L, r.Lab.A, r.Lab.B,
+		r.OKLab.L, r.OKLab.A, r.OKLab.B,
+		r.OKLCh.L, r.OKLCh.C, r.OKLCh.H,
+		r.YCbCr.Y, r.YCbCr.Cb, r.YCbCr.Cr,
+		r.YUV.Y, r.YUV.U, r.YUV.V,
+		r.YIQ.Y, r.YIQ.I, r.YIQ.Q,
+		r.Linear.R, r.Linear.G, r.Linear.B,
+		r.PQ.R, r.PQ.G, r.PQ.B,
+		r.HLG.R, r.HLG.G, r.HLG.B,
+		r.Alpha,
+		r.Gamut.Clip.R, r.Gamut.Clip.G, r.Gamut.Clip.B, r.Gamut.DeltaE,
+	)
+	for _, v := range r.Gamut.Input {
+		ok = ok && finiteFloats(v)
+	}
+	for _, s := range r.Spaces {
+		ok = ok && finiteFloats(s.R, s.G, s.B)
+	}
+	if r.Kelvin != nil {
+		ok = ok && finiteFloats(r.Kelvin.K, r.Kelvin.Duv)
+	}
+	if r.Adapted != nil {
+		a := r.Adapted
+		ok = ok && finiteFloats(a.XYZ.X, a.XYZ.Y, a.XYZ.Z, a.Lab.L, a.Lab.A, a.Lab.B)
+	}
+	return ok
+}
 
 func clampInt(v, low, high int) int {
 	if v < low {
@@ -287,15 +524,20 @@ func clampFloat(v, low, high float64) float64 {
 	return v
 }
 
+// addInputClip запоминает, на сколько входное значение канала выходит за допустимый диапазон
+func addInputClip(clip map[string]float64, channel string, v, low, high float64) {
+	if v < low {
+		clip[channel] = roundFloat(v-low, 2)
+	} else if v > high {
+		clip[channel] = roundFloat(v-high, 2)
+	}
+}
+
 func roundFloat(x float64, prec int) float64 {
 	p := math.Pow(10, float64(prec))
 	return math.Round(x*p) / p
 }
 
-func almostEqual(a, b float64) bool {
-	return math.Abs(a-b) < 1e-9
-}
-
 
 func init() {
 	dir := "static"
@@ -0,0 +1,264 @@
+package main
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+// modelValues - значения модели из ответа в виде, пригодном для ConvertRequest.Values
+func modelValues(t *testing.T, v any) map[string]float64 {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var values map[string]float64
+	if err := json.Unmarshal(data, &values); err != nil {
+		t.Fatal(err)
+	}
+	return values
+}
+
+// Значения любой модели из ответа, отправленные обратно, дают тот же RGB
+func TestConvertRoundTrip(t *testing.T) {
+	step := 17
+	if testing.Short() {
+		step = 51
+	}
+	for r := 0; r <= 255; r += step {
+		for g := 0; g <= 255; g += step {
+			for b := 0; b <= 255; b += step {
+				rgb := RGBModel{R: r, G: g, B: b}
+				resp, err := convertColor(ConvertRequest{Model: "rgb", Values: modelValues(t, rgb)})
+				if err != nil {
+					t.Fatal(err)
+				}
+				models := map[string]any{
+					"cmyk": resp.CMYK, "hsv": resp.HSV, "hsl": resp.HSL, "hwb": resp.HWB, "hsi": resp.HSI,
+					"xyz": resp.XYZ, "lab": resp.Lab, "oklab": resp.OKLab, "oklch": resp.OKLCh,
+					"ycbcr": resp.YCbCr, "yuv": resp.YUV, "yiq": resp.YIQ,
+				}
+				for model, v := range models {
+					back, err := convertColor(ConvertRequest{Model: model, Values: modelValues(t, v)})
+					if err != nil {
+						t.Fatalf("%s %v: %v", model, v, err)
+					}
+					if back.RGB != rgb {
+						t.Errorf("%v -> %s %v -> %v", rgb, model, v, back.RGB)
+					}
+				}
+			}
+		}
+	}
+}
+
+// Значения входной модели возвращаются без изменений, даже там, где модель вырождается
+func TestConvertKeepsInput(t *testing.T) {
+	cases := []struct {
+		model  string
+		values map[string]float64
+		got    func(ConvertResponse) any
+	}{
+		{"hsv", map[string]float64{"h": 200, "s": 0, "v": 50}, func(r ConvertResponse) any { return r.HSV }},
+		{"hsl", map[string]float64{"h": 123.45, "s": 67.89, "l": 40.12}, func(r ConvertResponse) any { return r.HSL }},
+		{"hwb", map[string]float64{"h": 300, "w": 60, "b": 40}, func(r ConvertResponse) any { return r.HWB }},
+		{"cmyk", map[string]float64{"c": 30, "m": 30, "y": 30, "k": 100}, func(r ConvertResponse) any { return r.CMYK }},
+		{"lab", map[string]float64{"l": 50, "a": 10, "b": -20}, func(r ConvertResponse) any { return r.Lab }},
+		{"oklch", map[string]float64{"l": 0.7, "c": 0, "h": 45}, func(r ConvertResponse) any { return r.OKLCh }},
+		{"ycbcr", map[string]float64{"y": 100, "cb": 90.5, "cr": 150.25}, func(r ConvertResponse) any { return r.YCbCr }},
+		{"yiq", map[string]float64{"y": 0.5, "i": 0.1, "q": -0.05}, func(r ConvertResponse) any { return r.YIQ }},
+	}
+	for _, c := range cases {
+		resp, err := convertColor(ConvertRequest{Model: c.model, Values: c.values})
+		if err != nil {
+			t.Fatalf("%s: %v", c.model, err)
+		}
+		got := modelValues(t, c.got(resp))
+		for k, want := range c.values {
+			if got[k] != want {
+				t.Errorf("%s %v: %s = %v, want %v", c.model, c.values, k, got[k], want)
+			}
+		}
+	}
+
+	// вне охвата значения считаются от приведенного цвета
+	resp, err := convertColor(ConvertRequest{Model: "hsv", Values: map[string]float64{"h": 0, "s": 120, "v": 100}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.HSV.S != 100 || resp.Gamut.InGamut {
+		t.Errorf("clipped hsv: %+v, gamut %+v", resp.HSV, resp.Gamut)
+	}
+}
+
+// Стандарт и диапазон запроса применяются и ко входу ycbcr, и к ответу
+func TestConvertVideoStandard(t *testing.T) {
+	req := ConvertRequest{Model: "ycbcr", Standard: "bt709", Range: "limited", Values: map[string]float64{"y": 235, "cb": 128, "cr": 128}}
+	resp, err := convertColor(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.RGB != (RGBModel{R: 255, G: 255, B: 255}) || resp.YCbCr.Y != 235 {
+		t.Errorf("limited white: rgb %v, ycbcr %+v", resp.RGB, resp.YCbCr)
+	}
+
+	resp, err = convertColor(ConvertRequest{Model: "rgb", Standard: "bt709", Values: map[string]float64{"r": 255, "g": 0, "b": 0}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.YCbCr.Y != 54.21 || resp.YUV.Y != 0.2126 {
+		t.Errorf("bt709 red: ycbcr %+v, yuv %+v", resp.YCbCr, resp.YUV)
+	}
+
+	for _, bad := range []ConvertRequest{
+		{Model: "rgb", Standard: "bt999", Values: map[string]float64{"r": 0, "g": 0, "b": 0}},
+		{Model: "ycbcr", Range: "tv", Values: map[string]float64{"y": 0, "cb": 0, "cr": 0}},
+		{Model: "yuv", Values: map[string]float64{"y": 0.5}},
+	} {
+		if _, err := convertColor(bad); err == nil {
+			t.Errorf("%+v: expected error", bad)
+		}
+	}
+}
+
+// Значения rgb в широком пространстве возвращаются без изменений, даже вне охвата sRGB
+func TestConvertRGBSpace(t *testing.T) {
+	values := map[string]float64{"r": 20.5, "g": 230, "b": 40}
+	resp, err := convertColor(ConvertRequest{Model: "rgb", RGBSpace: "display-p3", Values: values})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p3 := resp.Spaces["display-p3"]
+	if p3.R != 20.5 || p3.G != 230 || p3.B != 40 || !p3.Fits {
+		t.Errorf("display-p3 = %+v", p3)
+	}
+	if resp.Spaces["srgb"].Fits || resp.Gamut.InGamut || !resp.Spaces["rec2020"].Fits {
+		t.Errorf("fits: %+v, gamut %+v", resp.Spaces, resp.Gamut)
+	}
+
+	// та же точка, заданная через CSS, и обратно из rec2020
+	css, err := convertColor(ConvertRequest{CSS: "color(display-p3 0.0804 0.902 0.1569)"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := css.Spaces["rec2020"]
+	back, err := convertColor(ConvertRequest{Model: "rgb", RGBSpace: "rec2020", Values: modelValues(t, rec.SpaceRGB)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := back.Spaces["display-p3"]; math.Abs(got.G-230) > 0.05 || math.Abs(got.B-40) > 0.05 {
+		t.Errorf("display-p3 -> rec2020 -> display-p3 = %+v", got)
+	}
+
+	if _, err := convertColor(ConvertRequest{Model: "rgb", RGBSpace: "prophoto", Values: values}); err == nil {
+		t.Error("unknown rgb_space: expected error")
+	}
+}
+
+func TestConvertRejectsOverflow(t *testing.T) {
+	// конечные, но огромные значения переполняются в формулах - это ошибка запроса, а не NaN в ответе
+	for _, req := range []ConvertRequest{
+		{Model: "rgb", Values: map[string]float64{"r": 1e308, "g": 0, "b": 0}},
+		{Model: "lab", Values: map[string]float64{"l": 50, "a": 1e200, "b": 0}},
+	} {
+		if _, err := convertColor(req); err == nil {
+			t.Errorf("%s %v: expected error", req.Model, req.Values)
+		}
+	}
+}
+
+func TestConvertHDR(t *testing.T) {
+	// 10-битные значения возвращаются без потерь
+	resp, err := convertColor(ConvertRequest{Model: "rgb", BitDepth: 10, Values: map[string]float64{"r": 513, "g": 1, "b": 1023}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := resp.RGBDeep; d == nil || *d != (DeepRGBModel{Bits: 10, R: 513, G: 1, B: 1023}) {
+		t.Errorf("rgb_deep = %+v", d)
+	}
+
+	// линейное значение ярче белого: вне охвата sRGB, но PQ и linear его сохраняют
+	resp, err = convertColor(ConvertRequest{Model: "linear", Values: map[string]float64{"r": 4, "g": 4, "b": 4}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Gamut.InGamut || resp.RGB != (RGBModel{R: 255, G: 255, B: 255}) || resp.Linear.G != 4 {
+		t.Errorf("linear 4: rgb %v, linear %+v, gamut %+v", resp.RGB, resp.Linear, resp.Gamut)
+	}
+	back, err := convertColor(ConvertRequest{Model: "pq", Values: modelValues(t, resp.PQ)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(back.Linear.G-4) > 1e-3 {
+		t.Errorf("pq %+v -> linear %+v", resp.PQ, back.Linear)
+	}
+
+	// белый SDR в HLG - сигнал 0.75
+	resp, err = convertColor(ConvertRequest{Model: "hlg", Values: map[string]float64{"r": 0.75, "g": 0.75, "b": 0.75}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.RGB != (RGBModel{R: 255, G: 255, B: 255}) {
+		t.Errorf("hlg 0.75: rgb %v", resp.RGB)
+	}
+
+	// сигнал PQ вне 0..1 обрезается и отмечается, а не превращается в NaN
+	clipped, err := convertColor(ConvertRequest{Model: "pq", Values: map[string]float64{"r": 5, "g": 0, "b": 0}})
+	if err != nil {
+		t.Fatalf("pq r=5: %v", err)
+	}
+	if _, err := json.Marshal(clipped); err != nil {
+		t.Fatalf("pq r=5: %v", err)
+	}
+	if clipped.Gamut.Input["r"] != 4 || clipped.PQ.R != 1 {
+		t.Errorf("pq r=5: input_clip %v, pq %+v", clipped.Gamut.Input, clipped.PQ)
+	}
+
+	if _, err := convertColor(ConvertRequest{Model: "rgb", BitDepth: 9, Values: map[string]float64{"r": 0, "g": 0, "b": 0}}); err == nil {
+		t.Error("bit_depth 9: expected error")
+	}
+}
+
+func TestMixColors(t *testing.T) {
+	w := 3.0
+	resp, err := mixColors(MixRequest{Mode: "additive", Colors: []MixColor{
+		{Color: ConvertRequest{CSS: "red"}},
+		{Color: ConvertRequest{Model: "rgb", Values: map[string]float64{"r": 0, "g": 255, "b": 0}}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Color.RGB != (RGBModel{R: 255, G: 255, B: 0}) {
+		t.Errorf("additive red+green = %v", resp.Color.RGB)
+	}
+
+	// полупрозрачный цвет с весом 3 и непрозрачный белый: альфа - среднее по весам
+	resp, err = mixColors(MixRequest{Colors: []MixColor{
+		{Color: ConvertRequest{CSS: "rgb(0 0 255 / 0.2)"}, Weight: &w},
+		{Color: ConvertRequest{CSS: "white"}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Mode != "pigment" || math.Abs(resp.Color.Alpha-0.4) > 1e-9 || resp.Color.RGB.B <= resp.Color.RGB.R {
+		t.Errorf("pigment blue+white = %+v, alpha %v", resp.Color.RGB, resp.Color.Alpha)
+	}
+
+	// огромные веса: сумма не должна переполняться
+	huge := 1e308
+	resp, err = mixColors(MixRequest{Colors: []MixColor{
+		{Color: ConvertRequest{CSS: "rgb(255 0 0 / 0.5)"}, Weight: &huge},
+		{Color: ConvertRequest{CSS: "blue"}, Weight: &huge},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := json.Marshal(resp); err != nil || math.Abs(resp.Color.Alpha-0.75) > 1e-9 {
+		t.Errorf("weights 1e308: alpha %v, %v", resp.Color.Alpha, err)
+	}
+
+	if _, err := mixColors(MixRequest{Mode: "optical", Colors: []MixColor{{Color: ConvertRequest{CSS: "red"}}}}); err == nil {
+		t.Error("mode optical: expected error")
+	}
+}
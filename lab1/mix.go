@@ -0,0 +1,134 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+
+	"github.com/LordVillain/BSUComputerGraphicsLabs/lab1/colors"
+)
+
+// MixRequest - смешиваемые цвета (любые модели ConvertRequest) с весами
+type MixRequest struct {
+	Colors []MixColor `json:"colors"`
+	Mode   string     `json:"mode,omitempty"` // "additive" (свет), "subtractive" (фильтры), "pigment" (краски, по умолчанию)
+}
+
+type MixColor struct {
+	Color  ConvertRequest `json:"color"`
+	Weight *float64       `json:"weight,omitempty"` // доля в смеси (по умолчанию 1; веса нормируются)
+}
+
+type MixResponse struct {
+	Mode  string          `json:"mode"`
+	Color ConvertResponse `json:"color"`
+}
+
+// Максимальное число смешиваемых цветов
+const maxMixColors = 64
+
+var mixModes = map[string]colors.MixMode{
+	"additive":    colors.MixAdditive,
+	"subtractive": colors.MixSubtractive,
+	"pigment":     colors.MixPigment,
+}
+
+func mixHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req MixRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid json: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := mixColors(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	data, err := json.Marshal(resp)
+	if err != nil {
+		http.Error(w, "failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(append(data, '\n'))
+}
+
+func mixColors(req MixRequest) (MixResponse, error) {
+	if len(req.Colors) == 0 {
+		return MixResponse{}, errors.New("no colors")
+	}
+	if len(req.Colors) > maxMixColors {
+		return MixResponse{}, fmt.Errorf("at most %d colors allowed", maxMixColors)
+	}
+	if req.Mode == "" {
+		req.Mode = "pigment"
+	}
+	mode, ok := mixModes[req.Mode]
+	if !ok {
+		return MixResponse{}, errors.New("mode must be one of: additive, subtractive, pigment")
+	}
+
+	cs := make([]colors.Color, len(req.Colors))
+	weights := make([]float64, len(req.Colors))
+	alphas := make([]float64, len(req.Colors))
+	for i, c := range req.Colors {
+		// проверка и альфа - как у обычной конвертации; смешивается цвет до приведения в охват
+		resp, err := convertColor(c.Color)
+		if err != nil {
+			return MixResponse{}, fmt.Errorf("colors[%d]: %v", i, err)
+		}
+		if cs[i], err = requestColor(c.Color); err != nil {
+			return MixResponse{}, fmt.Errorf("colors[%d]: %v", i, err)
+		}
+		weights[i] = 1
+		if c.Weight != nil {
+			weights[i] = *c.Weight
+		}
+		alphas[i] = resp.Alpha
+	}
+
+	mixed, err := colors.Mix(cs, weights, mode)
+	if err != nil {
+		return MixResponse{}, err
+	}
+
+	// общие параметры (охват, профиль CMYK, фон) берутся у первого цвета, как в градиенте
+	first := req.Colors[0].Color
+	alpha := mixAlpha(alphas, weights)
+	color, err := convertColor(ConvertRequest{
+		Model:      "linear",
+		Values:     map[string]float64{"r": mixed.R, "g": mixed.G, "b": mixed.B},
+		Gamut:      first.Gamut,
+		Profile:    first.Profile,
+		Background: first.Background,
+		Alpha:      &alpha,
+	})
+	if err != nil {
+		return MixResponse{}, err
+	}
+	return MixResponse{Mode: req.Mode, Color: color}, nil
+}
+
+// mixAlpha - среднее альфа по весам. Веса уже проверены colors.Mix; они делятся
+// на наибольший, чтобы сумма огромных весов не переполнилась
+func mixAlpha(alphas, weights []float64) float64 {
+	strongest := 0.0
+	for _, v := range weights {
+		strongest = math.Max(strongest, v)
+	}
+	alpha, total := 0.0, 0.0
+	for i, a := range alphas {
+		alpha += weights[i] / strongest * a
+		total += weights[i] / strongest
+	}
+	return alpha / total
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/LordVillain/BSUComputerGraphicsLabs/lab1/colors"
+)
+
+// NamesRequest - цвет, для которого ищутся ближайшие именованные цвета
+type NamesRequest struct {
+	Color        ConvertRequest `json:"color"`
+	Dictionaries []string       `json:"dictionaries,omitempty"` // "css" (CSS/X11, по умолчанию), "ral", "user"
+	Palette      string         `json:"palette,omitempty"`      // user: содержимое файла палитры, строки "название #rrggbb"
+	K            int            `json:"k,omitempty"`            // число результатов (по умолчанию 5)
+	Metric       string         `json:"metric,omitempty"`       // "de2000" (по умолчанию), "de94", "de76"
+}
+
+type NamesResponse struct {
+	Color   ConvertResponse `json:"color"`
+	Metric  string          `json:"metric"`
+	Matches []NameMatch     `json:"matches"`
+}
+
+// NameMatch - именованный цвет и его отличие от запрошенного
+type NameMatch struct {
+	Name       string   `json:"name"`
+	Dictionary string   `json:"dictionary"`
+	Hex        string   `json:"hex"`
+	RGB        RGBModel `json:"rgb"`
+	DeltaE     float64  `json:"delta_e"`
+}
+
+// Ограничения запроса: число результатов и размер пользовательской палитры
+const (
+	maxNameMatches  = 50
+	maxPaletteSize  = 10000
+	defaultNamesNum = 5
+)
+
+// namedColor - элемент словаря с заранее рассчитанным Lab
+type namedColor struct {
+	name string
+	hex  string
+	rgb  colors.RGB
+	lab  colors.Lab
+}
+
+//go:embed names/ral.txt
+var ralNamesFile string
+
+// Встроенные словари цветов
+var nameDictionaries = map[string][]namedColor{
+	"css": mustParsePalette(cssNamesFile),
+	"ral": mustParsePalette(ralNamesFile),
+}
+
+func namesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req NamesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid json: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := nearestNames(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	data, err := json.Marshal(resp)
+	if err != nil {
+		http.Error(w, "failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(append(data, '\n'))
+}
+
+func nearestNames(req NamesRequest) (NamesResponse, error) {
+	if req.K == 0 {
+		req.K = defaultNamesNum
+	}
+	if req.K < 1 || req.K > maxNameMatches {
+		return NamesResponse{}, fmt.Errorf("k must be in 1..%d", maxNameMatches)
+	}
+	if req.Metric == "" {
+		req.Metric = "de2000"
+	}
+	var deltaE func(x1, x2 colors.Lab) float64
+	switch req.Metric {
+	case "de76":
+		deltaE = colors.DeltaE76
+	case "de94":
+		deltaE = colors.DeltaE94
+	case "de2000":
+		deltaE = colors.DeltaE2000
+	default:
+		return NamesResponse{}, errors.New("metric must be one of: de76, de94, de2000")
+	}
+	if len(req.Dictionaries) == 0 {
+		req.Dictionaries = []string{"css"}
+	}
+
+	color, err := convertColor(req.Color)
+	if err != nil {
+		return NamesResponse{}, errors.New("color: " + err.Error())
+	}
+	// как и в /api/delta, сравниваем запрошенный цвет до приведения в охват
+	lab, err := requestLab(req.Color)
+	if err != nil {
+		return NamesResponse{}, errors.New("color: " + err.Error())
+	}
+
+	var matches []NameMatch
+	for _, dict := range req.Dictionaries {
+		entries, ok := nameDictionaries[dict]
+		if dict == "user" {
+			if req.Palette == "" {
+				return NamesResponse{}, errors.New("dictionary user requires palette")
+			}
+			entries, err = parsePalette(req.Palette)
+			if err != nil {
+				return NamesResponse{}, err
+			}
+		} else if !ok {
+			return NamesResponse{}, errors.New("dictionaries must be one of: css, ral, user")
+		}
+		for _, e := range entries {
+			// Lab запроса конечен, но формулы (C⁷ в ΔE2000) могут переполниться, а NaN ломает сортировку
+			d := deltaE(lab, e.lab)
+			if !finiteFloats(d, roundFloat(d, 4)) {
+				return NamesResponse{}, errors.New("color: " + errOutOfRange.Error())
+			}
+			matches = append(matches, NameMatch{
+				Name:       e.name,
+				Dictionary: dict,
+				Hex:        e.hex,
+				RGB:        newRGBModel(e.rgb),
+				DeltaE:     d,
+			})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].DeltaE < matches[j].DeltaE })
+	if len(matches) > req.K {
+		matches = matches[:req.K]
+	}
+	for i := range matches {
+		matches[i].DeltaE = roundFloat(matches[i].DeltaE, 4)
+	}
+
+	return NamesResponse{Color: color, Metric: req.Metric, Matches: matches}, nil
+}
+
+// ---------- Файлы палитр ----------
+
+// parsePalette разбирает палитру: строки "название #rrggbb" (название может содержать пробелы),
+// строки с # в начале - комментарии.
+func parsePalette(data string) ([]namedColor, error) {
+	var entries []namedColor
+	for i, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		hex := strings.ToLower(fields[len(fields)-1])
+		if len(fields) < 2 || !strings.HasPrefix(hex, "#") {
+			return nil, fmt.Errorf("palette line %d: expected \"name #rrggbb\"", i+1)
+		}
+		r, g, b, _, err := parseHexColor(hex)
+		if err != nil {
+			return nil, fmt.Errorf("palette line %d: %v", i+1, err)
+		}
+		name := strings.Join(fields[:len(fields)-1], " ")
+		if len(entries) == maxPaletteSize {
+			return nil, fmt.Errorf("palette has more than %d colors", maxPaletteSize)
+		}
+		rgb := colors.RGB{R: r, G: g, B: b}
+		entries = append(entries, namedColor{name: name, hex: hex, rgb: rgb, lab: colors.ToLab(rgb)})
+	}
+	if len(entries) == 0 {
+		return nil, errors.New("palette is empty")
+	}
+	return entries, nil
+}
+
+func mustParsePalette(data string) []namedColor {
+	entries, err := parsePalette(data)
+	if err != nil {
+		panic(err)
+	}
+	return entries
+}
@@ -0,0 +1,52 @@
+package main
+
+import "testing"
+
+func TestNearestNames(t *testing.T) {
+	resp, err := nearestNames(NamesRequest{
+		Color:        ConvertRequest{CSS: "#f75e25"},
+		Dictionaries: []string{"css", "ral"},
+		K:            3,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Matches) != 3 {
+		t.Fatalf("got %d matches, want 3", len(resp.Matches))
+	}
+	if m := resp.Matches[0]; m.Name != "RAL 2008 Bright red orange" || m.DeltaE != 0 {
+		t.Errorf("first match = %+v", m)
+	}
+	for i := 1; i < len(resp.Matches); i++ {
+		if resp.Matches[i].DeltaE < resp.Matches[i-1].DeltaE {
+			t.Errorf("matches are not sorted: %+v", resp.Matches)
+		}
+	}
+
+	resp, err = nearestNames(NamesRequest{
+		Color:        ConvertRequest{CSS: "navy"},
+		Dictionaries: []string{"user"},
+		Palette:      "# моя палитра\nТемно-синий #000080\nБелый #fff\n",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Matches) != 2 || resp.Matches[0].Name != "Темно-синий" {
+		t.Errorf("user palette matches = %+v", resp.Matches)
+	}
+
+	bad := []NamesRequest{
+		{Color: ConvertRequest{CSS: "red"}, Dictionaries: []string{"pantone"}},
+		{Color: ConvertRequest{CSS: "red"}, Dictionaries: []string{"user"}},
+		{Color: ConvertRequest{CSS: "red"}, Dictionaries: []string{"user"}, Palette: "red ff0000"},
+		{Color: ConvertRequest{CSS: "red"}, K: 1000},
+		{Color: ConvertRequest{CSS: "red"}, Metric: "cmc"},
+		// огромное значение: ΔE не конечна
+		{Color: ConvertRequest{Model: "rgb", Values: map[string]float64{"r": 1e120, "g": 0, "b": 0}}},
+	}
+	for _, req := range bad {
+		if _, err := nearestNames(req); err == nil {
+			t.Errorf("%+v: expected an error", req)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+
+	"github.com/LordVillain/BSUComputerGraphicsLabs/lab1/colors"
+)
+
+// PaletteRequest - базовый цвет в любой модели ConvertRequest и параметры построения схем
+type PaletteRequest struct {
+	Color ConvertRequest `json:"color"`
+	Space string         `json:"space,omitempty"` // "hsv" (по умолчанию) или перцептивное "lch"
+	Count int            `json:"count,omitempty"` // число цветов в аналоговой и монохромной схемах (по умолчанию 5)
+	Angle float64        `json:"angle,omitempty"` // шаг тона для аналоговой схемы, градусы (по умолчанию 30)
+}
+
+// PaletteResponse - гармонические схемы; первый цвет каждой схемы - базовый
+type PaletteResponse struct {
+	Base               ConvertResponse   `json:"base"`
+	Space              string            `json:"space"`
+	Complementary      []ConvertResponse `json:"complementary"`
+	SplitComplementary []ConvertResponse `json:"split_complementary"`
+	Triadic            []ConvertResponse `json:"triadic"`
+	Tetradic           []ConvertResponse `json:"tetradic"`
+	Analogous          []ConvertResponse `json:"analogous"`
+	Monochromatic      []ConvertResponse `json:"monochromatic"`
+}
+
+func paletteHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req PaletteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid json: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := buildPalettes(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// hueWheel - цилиндрическое пространство, в котором строятся схемы:
+// тон можно поворачивать, а "светлоту" менять для монохромной схемы.
+type hueWheel interface {
+	rotate(deg float64) ConvertRequest
+	lightness(pct float64) ConvertRequest
+}
+
+// HSV: поворот H, монохромная схема по V
+type hsvWheel struct{ h, s, v float64 }
+
+func (w hsvWheel) rotate(deg float64) ConvertRequest {
+	return ConvertRequest{Model: "hsv", Values: map[string]float64{"h": colors.WrapHue(w.h + deg), "s": w.s, "v": w.v}}
+}
+
+func (w hsvWheel) lightness(pct float64) ConvertRequest {
+	return ConvertRequest{Model: "hsv", Values: map[string]float64{"h": w.h, "s": w.s, "v": pct}}
+}
+
+// LCh(ab): поворот h при неизменных L и C, монохромная схема по L
+type lchWheel struct{ l, c, h float64 }
+
+func (w lchWheel) rotate(deg float64) ConvertRequest {
+	return labRequest(colors.ToLab(colors.LCh{L: w.l, C: w.c, H: colors.WrapHue(w.h + deg)}))
+}
+
+func (w lchWheel) lightness(pct float64) ConvertRequest {
+	return labRequest(colors.ToLab(colors.LCh{L: pct, C: w.c, H: w.h}))
+}
+
+func labRequest(c colors.Lab) ConvertRequest {
+	return ConvertRequest{Model: "lab", Values: map[string]float64{"l": c.L, "a": c.A, "b": c.B}}
+}
+
+func buildPalettes(req PaletteRequest) (PaletteResponse, error) {
+	if req.Count == 0 {
+		req.Count = 5
+	}
+	if req.Count < 2 || req.Count > 24 {
+		return PaletteResponse{}, errors.New("count must be in 2..24")
+	}
+	if req.Angle == 0 {
+		req.Angle = 30
+	}
+
+	base, err := convertColor(req.Color)
+	if err != nil {
+		return PaletteResponse{}, errors.New("color: " + err.Error())
+	}
+
+	var wheel hueWheel
+	switch req.Space {
+	case "", "hsv":
+		req.Space = "hsv"
+		wheel = hsvWheel{h: base.HSV.H, s: base.HSV.S, v: base.HSV.V}
+	case "lch":
+		lch := colors.ToLCh(base.Lab)
+		wheel = lchWheel{l: lch.L, c: lch.C, h: lch.H}
+	default:
+		return PaletteResponse{}, errors.New("space must be one of: hsv, lch")
+	}
+
+	// цвета схем наследуют от базового стратегию охвата, профиль CMYK, альфу и фон
+	convert := func(c ConvertRequest) (ConvertResponse, error) {
+		c.Gamut = req.Color.Gamut
+		c.Profile = req.Color.Profile
+		c.Alpha = req.Color.Alpha
+		c.Background = req.Color.Background
+		return convertColor(c)
+	}
+	rotations := func(angles ...float64) ([]ConvertResponse, error) {
+		out := []ConvertResponse{base}
+		for _, a := range angles {
+			resp, err := convert(wheel.rotate(a))
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, resp)
+		}
+		return out, nil
+	}
+
+	resp := PaletteResponse{Base: base, Space: req.Space}
+	schemes := []struct {
+		dst    *[]ConvertResponse
+		angles []float64
+	}{
+		{&resp.Complementary, []float64{180}},
+		{&resp.SplitComplementary, []float64{150, 210}},
+		{&resp.Triadic, []float64{120, 240}},
+		{&resp.Tetradic, []float64{60, 180, 240}}, // прямоугольная схема
+		{&resp.Analogous, analogousAngles(req.Count, req.Angle)},
+	}
+	for _, s := range schemes {
+		if *s.dst, err = rotations(s.angles...); err != nil {
+			return PaletteResponse{}, err
+		}
+	}
+
+	// монохромная схема: базовый цвет и оттенки той же тональности
+	// с равномерно распределенной светлотой 20..95%
+	resp.Monochromatic = []ConvertResponse{base}
+	for i := 0; i < req.Count-1; i++ {
+		pct := 20 + 75*float64(i)/math.Max(float64(req.Count-2), 1)
+		swatch, err := convert(wheel.lightness(pct))
+		if err != nil {
+			return PaletteResponse{}, err
+		}
+		resp.Monochromatic = append(resp.Monochromatic, swatch)
+	}
+	return resp, nil
+}
+
+// analogousAngles - смещения соседних тонов по обе стороны от базового (без нуля)
+func analogousAngles(count int, step float64) []float64 {
+	var angles []float64
+	for i := 1; len(angles) < count-1; i++ {
+		angles = append(angles, -step*float64(i))
+		if len(angles) < count-1 {
+			angles = append(angles, step*float64(i))
+		}
+	}
+	return angles
+}
@@ -0,0 +1,281 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/LordVillain/BSUComputerGraphicsLabs/lab1/colors"
+)
+
+// SwatchSheetRequest - таблица образцов для вставки в документы
+type SwatchSheetRequest struct {
+	SwatchFile        // name - заголовок листа, colors - образцы
+	Format     string `json:"format,omitempty"`   // "png" (по умолчанию), "svg"
+	Columns    int    `json:"columns,omitempty"`  // образцов в ряду (по умолчанию не больше 4)
+	Scale      int    `json:"scale,omitempty"`    // масштаб 1..4 (по умолчанию 2)
+	Contrast   bool   `json:"contrast,omitempty"` // добавить контраст WCAG с белым и черным текстом
+}
+
+// Геометрия листа в точках шрифта (умножается на scale)
+const (
+	sheetPad        = 8
+	sheetCellWidth  = 140 // 22 символа по 6 точек и поля
+	sheetChipHeight = 64
+	sheetLineHeight = 10
+	sheetTitle      = 16
+	sheetLineChars  = 22
+
+	maxSheetColors  = 200
+	maxSheetColumns = 16
+	maxSheetLabel   = 256 // символов в заголовке и названии образца (на листе все равно обрезаются)
+)
+
+var (
+	sheetBackground = color.RGBA{255, 255, 255, 255}
+	sheetBorder     = color.RGBA{204, 204, 204, 255}
+	sheetText       = color.RGBA{34, 34, 34, 255}
+)
+
+// sheetSwatch - образец, готовый к выводу: цвет заливки и строки подписи
+type sheetSwatch struct {
+	fill  RGBModel
+	lines []string
+}
+
+// swatchSheet - разобранный запрос: образцы и размеры листа в точках
+type swatchSheet struct {
+	title    string
+	swatches []sheetSwatch
+	columns  int
+	lines    int // строк подписи у каждого образца
+	scale    int
+}
+
+// swatchSheetHandler отдает лист образцов картинкой PNG или SVG
+func swatchSheetHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req SwatchSheetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid json: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	sheet, err := newSwatchSheet(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var buf bytes.Buffer
+	contentType := "image/png"
+	if req.Format == "svg" {
+		contentType = "image/svg+xml"
+		sheet.writeSVG(&buf)
+	} else if err := png.Encode(&buf, sheet.image()); err != nil {
+		http.Error(w, "failed to encode png", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Write(buf.Bytes())
+}
+
+func newSwatchSheet(req SwatchSheetRequest) (*swatchSheet, error) {
+	if req.Format != "" && req.Format != "png" && req.Format != "svg" {
+		return nil, errors.New("format must be one of: png, svg")
+	}
+	if len(req.Colors) == 0 {
+		return nil, errors.New("no colors")
+	}
+	if len(req.Colors) > maxSheetColors {
+		return nil, fmt.Errorf("at most %d colors allowed", maxSheetColors)
+	}
+	if utf8.RuneCountInString(req.Name) > maxSheetLabel {
+		return nil, fmt.Errorf("name is longer than %d characters", maxSheetLabel)
+	}
+	for i, c := range req.Colors {
+		if utf8.RuneCountInString(c.Name) > maxSheetLabel {
+			return nil, fmt.Errorf("colors[%d]: name is longer than %d characters", i, maxSheetLabel)
+		}
+	}
+	sheet := &swatchSheet{title: req.Name, columns: req.Columns, scale: req.Scale}
+	if sheet.columns == 0 {
+		sheet.columns = min(len(req.Colors), 4)
+	}
+	if sheet.columns < 1 || sheet.columns > maxSheetColumns {
+		return nil, fmt.Errorf("columns must be in 1..%d", maxSheetColumns)
+	}
+	if sheet.scale == 0 {
+		sheet.scale = 2
+	}
+	if sheet.scale < 1 || sheet.scale > 4 {
+		return nil, errors.New("scale must be in 1..4")
+	}
+
+	named := false
+	for _, c := range req.Colors {
+		named = named || c.Name != ""
+	}
+	for i, c := range req.Colors {
+		resp, err := convertColor(c.Color)
+		if err != nil {
+			return nil, fmt.Errorf("colors[%d]: %v", i, err)
+		}
+		var lines []string
+		if named {
+			lines = append(lines, c.Name)
+		}
+		lines = append(lines, swatchLabels(resp, req.Contrast)...)
+		for j := range lines {
+			lines[j] = truncateLabel(lines[j])
+		}
+		sheet.swatches = append(sheet.swatches, sheetSwatch{fill: resp.Flattened, lines: lines})
+	}
+	sheet.lines = len(sheet.swatches[0].lines)
+	return sheet, nil
+}
+
+// swatchLabels - подпись образца: hex, CMYK, HSV и при необходимости контраст с белым и черным текстом
+func swatchLabels(resp ConvertResponse, contrast bool) []string {
+	lines := []string{
+		strings.ToUpper(resp.CSS.Hex),
+		fmt.Sprintf("CMYK %.0f/%.0f/%.0f/%.0f", resp.CMYK.C, resp.CMYK.M, resp.CMYK.Y, resp.CMYK.K),
+		fmt.Sprintf("HSV %.0f°/%.0f%%/%.0f%%", resp.HSV.H, resp.HSV.S, resp.HSV.V),
+	}
+	if contrast {
+		fill := resp.Flattened.color()
+		for _, text := range []struct {
+			name string
+			c    colors.RGB
+		}{
+			{"white", colors.RGB{R: 255, G: 255, B: 255}},
+			{"black", colors.RGB{}},
+		} {
+			ratio := colors.WCAGContrast(text.c, fill)
+			lines = append(lines, fmt.Sprintf("%s %.2f %s", text.name, ratio, wcagGrade(ratio)))
+		}
+	}
+	return lines
+}
+
+// wcagGrade - наивысший уровень WCAG 2.x, которому соответствует контраст
+func wcagGrade(ratio float64) string {
+	switch {
+	case ratio >= wcagLevels["AAA"]:
+		return "AAA"
+	case ratio >= wcagLevels["AA"]:
+		return "AA"
+	case ratio >= wcagLevels["AA-LARGE"]:
+		return "AA large"
+	}
+	return "fail"
+}
+
+// truncateLabel укорачивает строку до ширины ячейки. glyphText удлиняет кириллицу,
+// поэтому ширина считается по транслитерации, а режется исходная строка - за один проход
+func truncateLabel(s string) string {
+	width, cut := 0, 0
+	for i, r := range s {
+		if width <= sheetLineChars-2 {
+			cut = i // s[:i] помещается вместе с ".."
+		}
+		width += glyphLen(r)
+		if width > sheetLineChars {
+			return s[:cut] + ".."
+		}
+	}
+	return s
+}
+
+// cellHeight - высота ячейки образца в точках
+func (s *swatchSheet) cellHeight() int {
+	return sheetChipHeight + 4 + s.lines*sheetLineHeight
+}
+
+func (s *swatchSheet) titleHeight() int {
+	if s.title == "" {
+		return 0
+	}
+	return sheetTitle
+}
+
+// size - размеры листа в точках (без учета scale)
+func (s *swatchSheet) size() (int, int) {
+	rows := (len(s.swatches) + s.columns - 1) / s.columns
+	return sheetPad + s.columns*(sheetCellWidth+sheetPad),
+		sheetPad + s.titleHeight() + rows*(s.cellHeight()+sheetPad)
+}
+
+// cellOrigin - левый верхний угол i-го образца в точках
+func (s *swatchSheet) cellOrigin(i int) (int, int) {
+	return sheetPad + i%s.columns*(sheetCellWidth+sheetPad),
+		sheetPad + s.titleHeight() + i/s.columns*(s.cellHeight()+sheetPad)
+}
+
+func (s *swatchSheet) image() image.Image {
+	k := s.scale
+	w, h := s.size()
+	img := image.NewRGBA(image.Rect(0, 0, w*k, h*k))
+	draw.Draw(img, img.Bounds(), image.NewUniform(sheetBackground), image.Point{}, draw.Src)
+	if s.title != "" {
+		drawText(img, sheetPad*k, sheetPad*k, k, glyphText(truncateLabel(s.title)), sheetText)
+	}
+
+	for i, sw := range s.swatches {
+		x, y := s.cellOrigin(i)
+		chip := image.Rect(x*k, y*k, (x+sheetCellWidth)*k, (y+sheetChipHeight)*k)
+		draw.Draw(img, chip, image.NewUniform(sheetBorder), image.Point{}, draw.Src)
+		fill := color.RGBA{uint8(sw.fill.R), uint8(sw.fill.G), uint8(sw.fill.B), 255}
+		draw.Draw(img, chip.Inset(k), image.NewUniform(fill), image.Point{}, draw.Src)
+
+		for j, line := range sw.lines {
+			drawText(img, (x+2)*k, (y+sheetChipHeight+4+j*sheetLineHeight)*k, k, glyphText(line), sheetText)
+		}
+	}
+	return img
+}
+
+// writeSVG - тот же лист векторно; подписи выводятся как текст, поэтому кириллица сохраняется
+func (s *swatchSheet) writeSVG(buf *bytes.Buffer) {
+	w, h := s.size()
+	fmt.Fprintf(buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n",
+		w*s.scale, h*s.scale, w, h)
+	fmt.Fprintf(buf, `<rect width="%d" height="%d" fill="%s"/>`+"\n", w, h, svgColor(sheetBackground))
+	fmt.Fprintf(buf, `<g font-family="monospace" font-size="9" fill="%s">`+"\n", svgColor(sheetText))
+	if s.title != "" {
+		fmt.Fprintf(buf, `<text x="%d" y="%d">`, sheetPad, sheetPad+glyphHeight)
+		xml.EscapeText(buf, []byte(truncateLabel(s.title)))
+		buf.WriteString("</text>\n")
+	}
+
+	for i, sw := range s.swatches {
+		x, y := s.cellOrigin(i)
+		fmt.Fprintf(buf, `<rect x="%g" y="%g" width="%d" height="%d" fill="#%02x%02x%02x" stroke="%s"/>`+"\n",
+			float64(x)+0.5, float64(y)+0.5, sheetCellWidth-1, sheetChipHeight-1,
+			sw.fill.R, sw.fill.G, sw.fill.B, svgColor(sheetBorder))
+		for j, line := range sw.lines {
+			fmt.Fprintf(buf, `<text x="%d" y="%d">`, x+2, y+sheetChipHeight+4+j*sheetLineHeight+glyphHeight)
+			xml.EscapeText(buf, []byte(line))
+			buf.WriteString("</text>\n")
+		}
+	}
+	buf.WriteString("</g>\n</svg>\n")
+}
+
+func svgColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
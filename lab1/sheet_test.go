@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSwatchSheet(t *testing.T) {
+	body := `{"name":"Тест","columns":1,"scale":1,"contrast":true,"colors":[
+		{"name":"Кирпич <1>","color":{"css":"#b5523b"}},
+		{"color":{"model":"cmyk","values":{"c":0,"m":0,"y":0,"k":100}}}]}`
+	rec := httptest.NewRecorder()
+	swatchSheetHandler(rec, httptest.NewRequest(http.MethodPost, "/api/swatches/render", strings.NewReader(body)))
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "image/png" {
+		t.Fatalf("status %d, %s: %s", rec.Code, rec.Header().Get("Content-Type"), rec.Body)
+	}
+	img, err := png.Decode(rec.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// один столбец, заголовок, два образца по 6 строк подписи
+	sheet := swatchSheet{title: "Тест", columns: 1, lines: 6, swatches: make([]sheetSwatch, 2)}
+	w, h := sheet.size()
+	if b := img.Bounds(); b.Dx() != w || b.Dy() != h {
+		t.Fatalf("size %v, want %dx%d", b, w, h)
+	}
+	x, y := sheet.cellOrigin(1)
+	if r, g, b, _ := img.At(x+10, y+10).RGBA(); r != 0 || g != 0 || b != 0 {
+		t.Errorf("second swatch is not black: %d %d %d", r, g, b)
+	}
+
+	one := []SwatchColor{{Name: "Кирпич <1>", Color: ConvertRequest{CSS: "#b5523b"}}}
+	s, err := newSwatchSheet(SwatchSheetRequest{SwatchFile: SwatchFile{Colors: one}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	s.writeSVG(&buf)
+	svg := buf.String()
+	for _, want := range []string{`fill="#b5523b"`, "Кирпич &lt;1&gt;", "#B5523B", "CMYK 0/55/67/29", "HSV 11°/67%/71%"} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("svg has no %q", want)
+		}
+	}
+
+	for _, bad := range []SwatchSheetRequest{
+		{},
+		{Format: "gif", SwatchFile: SwatchFile{Colors: one}},
+		{Columns: 17, SwatchFile: SwatchFile{Colors: one}},
+		{Scale: 5, SwatchFile: SwatchFile{Colors: one}},
+		{SwatchFile: SwatchFile{Name: strings.Repeat("я", maxSheetLabel+1), Colors: one}},
+		{SwatchFile: SwatchFile{Colors: []SwatchColor{{Name: strings.Repeat("я", maxSheetLabel+1), Color: one[0].Color}}}},
+	} {
+		if _, err := newSwatchSheet(bad); err == nil {
+			t.Errorf("%+v: expected error", bad)
+		}
+	}
+}
+
+func TestGlyphText(t *testing.T) {
+	if got := glyphText("Щука Ёж 5° €"); got != "Shchuka Ezh 5° ?" {
+		t.Errorf("glyphText = %q", got)
+	}
+	for s, want := range map[string]string{
+		"Очень длинное название цвета": "Очень длинное назван..",
+		"Ровно двадцать два си":        "Ровно двадцать два си",
+		"Щщщщщщ":                        "Щщщщщ..",
+		"ascii only, but too long here": "ascii only, but too ..",
+	} {
+		if got := truncateLabel(s); got != want {
+			t.Errorf("truncateLabel(%q) = %q, want %q", s, got, want)
+		}
+	}
+}
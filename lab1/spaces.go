@@ -0,0 +1,53 @@
+package main
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/LordVillain/BSUComputerGraphicsLabs/lab1/colors"
+)
+
+// SpaceModel - цвет в одном из рабочих RGB-пространств: каналы 0..255 с 2 знаками
+// (обрезанные до охвата пространства) и признак того, что цвет в охват попадает
+type SpaceModel struct {
+	colors.SpaceRGB
+	Fits bool `json:"fits"`
+}
+
+// Рабочие пространства по имени: "srgb", "display-p3", "adobe-rgb", "rec2020"
+var rgbSpaces = func() map[string]*colors.RGBSpace {
+	m := map[string]*colors.RGBSpace{}
+	for _, s := range colors.RGBSpaces {
+		m[s.Name] = s
+	}
+	return m
+}()
+
+func parseRGBSpace(name string) (*colors.RGBSpace, error) {
+	if name == "" {
+		return colors.SRGB, nil
+	}
+	s, ok := rgbSpaces[name]
+	if !ok {
+		names := make([]string, len(colors.RGBSpaces))
+		for i, s := range colors.RGBSpaces {
+			names[i] = s.Name
+		}
+		return nil, errors.New("rgb_space must be one of: " + strings.Join(names, ", "))
+	}
+	return s, nil
+}
+
+// spaceModels - цвет во всех рабочих пространствах. Считается от исходного цвета,
+// а не от приведенного в охват sRGB: иначе цвета, которые есть в широком
+// пространстве, но не в sRGB, терялись бы.
+func spaceModels(color colors.Color) map[string]SpaceModel {
+	models := map[string]SpaceModel{}
+	for _, s := range colors.RGBSpaces {
+		models[s.Name] = SpaceModel{
+			SpaceRGB: colors.ClipToSpace(color, s).Rounded(),
+			Fits:     colors.InSpaceGamut(color, s),
+		}
+	}
+	return models
+}
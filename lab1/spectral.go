@@ -0,0 +1,310 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/LordVillain/BSUComputerGraphicsLabs/lab1/colors"
+)
+
+// SpectralRequest - измеренные спектры отражения и условия наблюдения.
+// Образцы задаются списком samples или текстом csv (одно из двух).
+type SpectralRequest struct {
+	Illuminant string           `json:"illuminant,omitempty"` // источник: "D65" (по умолчанию), "D50", "A", "E"
+	Observer   string           `json:"observer,omitempty"`   // наблюдатель: "2" (CIE 1931, по умолчанию), "10" (CIE 1964)
+	Percent    bool             `json:"percent,omitempty"`    // значения в процентах 0..100, а не 0..1
+	Samples    []SpectralSample `json:"samples,omitempty"`
+	CSV        string           `json:"csv,omitempty"`
+
+	Gamut      string `json:"gamut,omitempty"`      // как в ConvertRequest
+	Adaptation string `json:"adaptation,omitempty"` // адаптация от белой точки источника к D65: "bradford" (по умолчанию), "vonkries", "xyz"
+}
+
+type SpectralSample struct {
+	Name        string    `json:"name,omitempty"`
+	Wavelengths []float64 `json:"wavelengths"` // нм, по возрастанию
+	Values      []float64 `json:"values"`      // коэффициент отражения на каждой длине волны
+}
+
+type SpectralResponse struct {
+	Illuminant string           `json:"illuminant"`
+	Observer   string           `json:"observer"`
+	White      colors.XYZ       `json:"white"` // белая точка источника для наблюдателя, Y = 100
+	Samples    []SpectralResult `json:"samples"`
+}
+
+type SpectralResult struct {
+	Name  string          `json:"name,omitempty"`
+	XYZ   colors.XYZ      `json:"xyz"`   // относительно белой точки источника (white)
+	Color ConvertResponse `json:"color"` // после адаптации к D65, как в /api/convert
+}
+
+// Ограничения на размер запроса и число образцов
+const (
+	maxSpectralBody    = 4 << 20
+	maxSpectralSamples = 1000
+)
+
+// spectralHandler принимает SpectralRequest в JSON или CSV-файл как есть
+// (Content-Type: text/csv или text/plain); во втором случае параметры
+// illuminant, observer, percent, gamut и adaptation берутся из строки запроса.
+func spectralHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxSpectralBody)
+	var req SpectralRequest
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "text/csv" || mediaType == "text/plain" {
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "read error: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		q := r.URL.Query()
+		req = SpectralRequest{
+			Illuminant: q.Get("illuminant"),
+			Observer:   q.Get("observer"),
+			Percent:    q.Get("percent") == "true" || q.Get("percent") == "1",
+			CSV:        string(data),
+			Gamut:      q.Get("gamut"),
+			Adaptation: q.Get("adaptation"),
+		}
+	} else if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid json: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := spectralColors(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+func parseIlluminant(name string) (colors.Illuminant, string, error) {
+	switch strings.ToUpper(name) {
+	case "", "D65":
+		return colors.IlluminantD65, "D65", nil
+	case "D50":
+		return colors.IlluminantD50, "D50", nil
+	case "A":
+		return colors.IlluminantA, "A", nil
+	case "E":
+		return colors.IlluminantE, "E", nil
+	}
+	return 0, "", errors.New("illuminant must be one of: D65, D50, A, E")
+}
+
+func parseObserver(name string) (colors.Observer, string, error) {
+	switch name {
+	case "", "2":
+		return colors.Observer2, "2", nil
+	case "10":
+		return colors.Observer10, "10", nil
+	}
+	return 0, "", errors.New("observer must be one of: 2, 10")
+}
+
+func spectralColors(req SpectralRequest) (SpectralResponse, error) {
+	ill, illName, err := parseIlluminant(req.Illuminant)
+	if err != nil {
+		return SpectralResponse{}, err
+	}
+	obs, obsName, err := parseObserver(req.Observer)
+	if err != nil {
+		return SpectralResponse{}, err
+	}
+	method, err := parseAdaptation(req.Adaptation)
+	if err != nil {
+		return SpectralResponse{}, err
+	}
+
+	samples := req.Samples
+	if req.CSV != "" {
+		if len(samples) > 0 {
+			return SpectralResponse{}, errors.New("specify either samples or csv, not both")
+		}
+		if samples, err = parseSpectralCSV(req.CSV); err != nil {
+			return SpectralResponse{}, err
+		}
+	}
+	if len(samples) == 0 {
+		return SpectralResponse{}, errors.New("no samples")
+	}
+	if len(samples) > maxSpectralSamples {
+		return SpectralResponse{}, fmt.Errorf("at most %d samples allowed", maxSpectralSamples)
+	}
+
+	white := colors.IlluminantWhite(ill, obs)
+	resp := SpectralResponse{Illuminant: illName, Observer: obsName, White: white.Rounded()}
+	for i, s := range samples {
+		values := s.Values
+		if req.Percent {
+			values = make([]float64, len(s.Values))
+			for j, v := range s.Values {
+				values[j] = v / 100
+			}
+		}
+		xyz, err := colors.SpectrumToXYZ(colors.Spectrum{Wavelengths: s.Wavelengths, Values: values}, ill, obs)
+		if err != nil {
+			return SpectralResponse{}, fmt.Errorf("sample %d: %v", i+1, err)
+		}
+
+		// остальные модели считаются для D65: переносим цвет от белой точки источника.
+		// Для наблюдателя 10° это приближение - модели построены на функциях 2°.
+		d65 := colors.Adapt(xyz, white, colors.WhiteD65, method)
+		color, err := convertColor(ConvertRequest{
+			Model:  "xyz",
+			Values: map[string]float64{"x": d65.X, "y": d65.Y, "z": d65.Z},
+			Gamut:  req.Gamut,
+		})
+		if err != nil {
+			return SpectralResponse{}, err
+		}
+		resp.Samples = append(resp.Samples, SpectralResult{Name: s.Name, XYZ: xyz.Rounded(), Color: color})
+	}
+	return resp, nil
+}
+
+// parseSpectralCSV разбирает таблицу спектров в одном из двух видов:
+//
+//	nm,образец1,образец2     - длины волн в первом столбце, образцы в столбцах (заголовок необязателен)
+//	name,380,390,400,...     - длины волн в заголовке, образцы в строках (столбец имен необязателен)
+//
+// Разделитель - запятая, точка с запятой или табуляция; при двух последних дробная часть
+// может отделяться запятой. Строки, начинающиеся с #, пропускаются.
+func parseSpectralCSV(data string) ([]SpectralSample, error) {
+	comma := detectDelimiter(data)
+	r := csv.NewReader(strings.NewReader(data))
+	r.Comma = comma
+	r.Comment = '#'
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, errors.New("invalid csv: " + err.Error())
+	}
+	if len(records) < 2 {
+		return nil, errors.New("csv needs at least 2 rows")
+	}
+
+	num := func(cell string) (float64, bool) {
+		cell = strings.TrimSpace(cell)
+		if comma != ',' {
+			cell = strings.Replace(cell, ",", ".", 1)
+		}
+		v, err := strconv.ParseFloat(cell, 64)
+		return v, err == nil
+	}
+
+	// длины волн в заголовке: не меньше трех чисел в видимом и ближнем диапазоне
+	wavelengthCells := 0
+	for _, cell := range records[0] {
+		if v, ok := num(cell); ok && v >= 300 && v <= 1000 {
+			wavelengthCells++
+		}
+	}
+
+	if wavelengthCells >= 3 {
+		header := records[0]
+		offset := 0
+		if _, ok := num(header[0]); !ok {
+			offset = 1
+		}
+		wavelengths := make([]float64, len(header)-offset)
+		for i, cell := range header[offset:] {
+			v, ok := num(cell)
+			if !ok {
+				return nil, fmt.Errorf("csv header: %q is not a wavelength", cell)
+			}
+			wavelengths[i] = v
+		}
+		var samples []SpectralSample
+		for i, rec := range records[1:] {
+			if len(rec) != len(header) {
+				return nil, fmt.Errorf("csv row %d: %d cells, want %d", i+2, len(rec), len(header))
+			}
+			s := SpectralSample{Wavelengths: wavelengths, Values: make([]float64, len(wavelengths))}
+			if offset == 1 {
+				s.Name = strings.TrimSpace(rec[0])
+			}
+			for j, cell := range rec[offset:] {
+				v, ok := num(cell)
+				if !ok {
+					return nil, fmt.Errorf("csv row %d: %q is not a number", i+2, cell)
+				}
+				s.Values[j] = v
+			}
+			samples = append(samples, s)
+		}
+		return samples, nil
+	}
+
+	// длины волн в первом столбце; заголовок - если первая ячейка не число
+	header, rows := records[0], records
+	if _, ok := num(header[0]); !ok {
+		rows = records[1:]
+	} else {
+		header = nil
+	}
+	columns := len(records[0])
+	if columns < 2 {
+		return nil, errors.New("csv needs a wavelength column and at least one sample column")
+	}
+	samples := make([]SpectralSample, columns-1)
+	for j := range samples {
+		if header != nil {
+			samples[j].Name = strings.TrimSpace(header[j+1])
+		}
+	}
+	for i, rec := range rows {
+		line := i + len(records) - len(rows) + 1
+		if len(rec) != columns {
+			return nil, fmt.Errorf("csv row %d: %d cells, want %d", line, len(rec), columns)
+		}
+		nm, ok := num(rec[0])
+		if !ok {
+			return nil, fmt.Errorf("csv row %d: %q is not a wavelength", line, rec[0])
+		}
+		for j, cell := range rec[1:] {
+			v, ok := num(cell)
+			if !ok {
+				return nil, fmt.Errorf("csv row %d: %q is not a number", line, cell)
+			}
+			samples[j].Wavelengths = append(samples[j].Wavelengths, nm)
+			samples[j].Values = append(samples[j].Values, v)
+		}
+	}
+	return samples, nil
+}
+
+// detectDelimiter - разделитель по первой значащей строке: табуляция, точка с запятой или запятая
+func detectDelimiter(data string) rune {
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		switch {
+		case strings.Contains(line, "\t"):
+			return '\t'
+		case strings.Contains(line, ";"):
+			return ';'
+		}
+		return ','
+	}
+	return ','
+}
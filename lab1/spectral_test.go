@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestParseSpectralCSV(t *testing.T) {
+	long := "# измерение\nnm;white;gray\n400;100;50\n500;100;50\n600;100;50\n700;100,0;50,0\n"
+	samples, err := parseSpectralCSV(long)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 2 || samples[1].Name != "gray" || len(samples[1].Values) != 4 || samples[1].Values[3] != 50 {
+		t.Fatalf("long format: %+v", samples)
+	}
+
+	wide := "name,400,500,600,700\nred,0.05,0.05,0.8,0.9\nblue,0.7,0.3,0.05,0.05\n"
+	if samples, err = parseSpectralCSV(wide); err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 2 || samples[0].Name != "red" || samples[0].Wavelengths[2] != 600 || samples[1].Values[0] != 0.7 {
+		t.Fatalf("wide format: %+v", samples)
+	}
+
+	for _, bad := range []string{"400,0.5\n", "400,0.5\n500,x\n", "name,400,500,600\nred,1,1\n"} {
+		if _, err := parseSpectralCSV(bad); err == nil {
+			t.Errorf("%q: expected error", bad)
+		}
+	}
+}
+
+func TestSpectralHandler(t *testing.T) {
+	// идеальный белый и серый в процентах под D50: после адаптации к D65 - белый и серый sRGB
+	body := "nm,white,gray\n380,100,18\n780,100,18\n"
+	req := httptest.NewRequest(http.MethodPost, "/api/spectral?illuminant=d50&percent=true", strings.NewReader(body))
+	req.Header.Set("Content-Type", "text/csv; charset=utf-8")
+	rec := httptest.NewRecorder()
+	spectralHandler(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body)
+	}
+
+	var resp SpectralResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	white, gray := resp.Samples[0], resp.Samples[1]
+	if resp.Illuminant != "D50" || math.Abs(white.XYZ.X-96.42) > 0.05 || math.Abs(white.XYZ.Z-82.52) > 0.05 {
+		t.Errorf("white point: %+v", white.XYZ)
+	}
+	if white.Color.RGB != (RGBModel{R: 255, G: 255, B: 255}) {
+		t.Errorf("white rgb %v", white.Color.RGB)
+	}
+	if g := gray.Color.RGB; g.R != g.G || g.G != g.B || math.Abs(gray.XYZ.Y-18) > 1e-6 {
+		t.Errorf("gray rgb %v, xyz %+v", g, gray.XYZ)
+	}
+
+	if _, err := spectralColors(SpectralRequest{Observer: "5", CSV: body}); err == nil {
+		t.Error("observer 5: expected error")
+	}
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/LordVillain/BSUComputerGraphicsLabs/lab1/colors"
+)
+
+// SwatchFile - палитра: набор именованных цветов в любых моделях ConvertRequest.
+// В таком же виде палитра хранится в формате json.
+type SwatchFile struct {
+	Name   string        `json:"name,omitempty"`
+	Colors []SwatchColor `json:"colors"`
+}
+
+type SwatchColor struct {
+	Name  string         `json:"name,omitempty"`
+	Group string         `json:"group,omitempty"` // группа (папка) образцов в ASE
+	Color ConvertRequest `json:"color"`
+}
+
+// SwatchExportRequest - палитра для выгрузки в файл
+type SwatchExportRequest struct {
+	SwatchFile
+	Format string `json:"format"`          // "ase", "aco", "gpl", "json"
+	Model  string `json:"model,omitempty"` // модель цветов в ase и aco: "rgb" (по умолчанию), "cmyk", "lab"
+}
+
+// SwatchImportResponse - разобранная палитра, каждый цвет во всех моделях
+type SwatchImportResponse struct {
+	Format string           `json:"format"`
+	Name   string           `json:"name,omitempty"`
+	Colors []ImportedSwatch `json:"colors"`
+}
+
+type ImportedSwatch struct {
+	Name  string          `json:"name,omitempty"`
+	Group string          `json:"group,omitempty"`
+	Color ConvertResponse `json:"color"`
+}
+
+// Максимальный размер загружаемого файла палитры
+const maxSwatchFileSize = 10 << 20
+
+// swatchEntry - цвет, подготовленный к записи в файл
+type swatchEntry struct {
+	name  string
+	group string
+	req   ConvertRequest
+	resp  ConvertResponse
+}
+
+// swatchFormat - чтение и запись одного формата файлов палитр
+type swatchFormat struct {
+	ext         string
+	contentType string
+	decode      func(data []byte) (SwatchFile, error)
+	encode      func(name string, entries []swatchEntry, model string) ([]byte, error)
+}
+
+var swatchFormats = map[string]swatchFormat{
+	"ase":  {"ase", "application/octet-stream", decodeASE, encodeASE},
+	"aco":  {"aco", "application/octet-stream", decodeACO, encodeACO},
+	"gpl":  {"gpl", "text/plain; charset=utf-8", decodeGPL, encodeGPL},
+	"json": {"json", "application/json", decodeSwatchJSON, encodeSwatchJSON},
+}
+
+// swatchImportHandler принимает файл палитры телом запроса.
+// Формат задается параметром ?format=, без него определяется по содержимому.
+func swatchImportHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxSwatchFileSize))
+	if err != nil {
+		http.Error(w, "read error: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := importSwatches(data, r.URL.Query().Get("format"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+func swatchExportHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req SwatchExportRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid json: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	data, format, err := exportSwatches(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", format.contentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="palette.`+format.ext+`"`)
+	w.Write(data)
+}
+
+func importSwatches(data []byte, format string) (SwatchImportResponse, error) {
+	if format == "" {
+		format = detectSwatchFormat(data)
+	}
+	f, ok := swatchFormats[format]
+	if !ok {
+		return SwatchImportResponse{}, errors.New("format must be one of: ase, aco, gpl, json")
+	}
+
+	file, err := f.decode(data)
+	if err != nil {
+		return SwatchImportResponse{}, fmt.Errorf("%s: %v", format, err)
+	}
+	if len(file.Colors) > maxPaletteSize {
+		return SwatchImportResponse{}, fmt.Errorf("palette has more than %d colors", maxPaletteSize)
+	}
+
+	resp := SwatchImportResponse{Format: format, Name: file.Name, Colors: make([]ImportedSwatch, len(file.Colors))}
+	for i, c := range file.Colors {
+		color, err := convertColor(c.Color)
+		if err != nil {
+			return SwatchImportResponse{}, fmt.Errorf("colors[%d]: %v", i, err)
+		}
+		resp.Colors[i] = ImportedSwatch{Name: c.Name, Group: c.Group, Color: color}
+	}
+	return resp, nil
+}
+
+func exportSwatches(req SwatchExportRequest) ([]byte, swatchFormat, error) {
+	f, ok := swatchFormats[req.Format]
+	if !ok {
+		return nil, f, errors.New("format must be one of: ase, aco, gpl, json")
+	}
+	if req.Model == "" {
+		req.Model = "rgb"
+	}
+	if req.Model != "rgb" && req.Model != "cmyk" && req.Model != "lab" {
+		return nil, f, errors.New("model must be one of: rgb, cmyk, lab")
+	}
+	if len(req.Colors) > maxPaletteSize {
+		return nil, f, fmt.Errorf("palette has more than %d colors", maxPaletteSize)
+	}
+
+	entries := make([]swatchEntry, len(req.Colors))
+	for i, c := range req.Colors {
+		resp, err := convertColor(c.Color)
+		if err != nil {
+			return nil, f, fmt.Errorf("colors[%d]: %v", i, err)
+		}
+		entries[i] = swatchEntry{name: c.Name, group: c.Group, req: c.Color, resp: resp}
+	}
+	data, err := f.encode(req.Name, entries, req.Model)
+	return data, f, err
+}
+
+// detectSwatchFormat определяет формат по сигнатуре файла
+func detectSwatchFormat(data []byte) string {
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case bytes.HasPrefix(data, []byte("ASEF")):
+		return "ase"
+	case bytes.HasPrefix(trimmed, []byte("GIMP Palette")):
+		return "gpl"
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		return "json"
+	case len(data) >= 2 && data[0] == 0 && (data[1] == 1 || data[1] == 2):
+		return "aco"
+	}
+	return ""
+}
+
+// swatchLab - Lab цвета относительно D50, как в палитрах Adobe (и в CSS)
+func swatchLab(resp ConvertResponse) colors.Lab {
+	return colors.LabFromXYZ(colors.AdaptD65ToD50(resp.XYZ), colors.WhiteD50)
+}
+
+// ---------- JSON ----------
+
+func decodeSwatchJSON(data []byte) (SwatchFile, error) {
+	var file SwatchFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return file, err
+	}
+	return file, nil
+}
+
+// encodeSwatchJSON сохраняет цвета в том виде, в каком они заданы в запросе (model не используется)
+func encodeSwatchJSON(name string, entries []swatchEntry, model string) ([]byte, error) {
+	file := SwatchFile{Name: name, Colors: make([]SwatchColor, len(entries))}
+	for i, e := range entries {
+		file.Colors[i] = SwatchColor{Name: e.name, Group: e.group, Color: e.req}
+	}
+	return json.MarshalIndent(file, "", "  ")
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSwatchesRoundTrip(t *testing.T) {
+	palette := SwatchFile{
+		Name: "Тест",
+		Colors: []SwatchColor{
+			{Name: "Красный", Group: "Основные", Color: ConvertRequest{CSS: "#ff0000"}},
+			{Name: "Бирюзовый", Group: "Основные", Color: ConvertRequest{CSS: "#30d5c8"}},
+			{Name: "Синий", Color: ConvertRequest{Model: "lab", Values: map[string]float64{"l": 30, "a": 20, "b": -60}}},
+		},
+	}
+	want := make([]RGBModel, len(palette.Colors))
+	for i, c := range palette.Colors {
+		resp, err := convertColor(c.Color)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want[i] = resp.RGB
+	}
+
+	for _, format := range []string{"ase", "aco", "gpl", "json"} {
+		for _, model := range []string{"rgb", "cmyk", "lab"} {
+			data, _, err := exportSwatches(SwatchExportRequest{SwatchFile: palette, Format: format, Model: model})
+			if err != nil {
+				t.Fatalf("%s/%s: export: %v", format, model, err)
+			}
+			if got := detectSwatchFormat(data); got != format {
+				t.Errorf("%s/%s: detected as %q", format, model, got)
+			}
+			resp, err := importSwatches(data, "")
+			if err != nil {
+				t.Fatalf("%s/%s: import: %v", format, model, err)
+			}
+			if len(resp.Colors) != len(want) {
+				t.Fatalf("%s/%s: got %d colors", format, model, len(resp.Colors))
+			}
+			for i, c := range resp.Colors {
+				if c.Name != palette.Colors[i].Name || c.Color.RGB != want[i] {
+					t.Errorf("%s/%s: colors[%d] = %q %v, want %q %v", format, model, i, c.Name, c.Color.RGB, palette.Colors[i].Name, want[i])
+				}
+				if (format == "ase" || format == "json") && c.Group != palette.Colors[i].Group {
+					t.Errorf("%s/%s: colors[%d] group = %q", format, model, i, c.Group)
+				}
+			}
+		}
+	}
+}
+
+func TestSwatchesImportErrors(t *testing.T) {
+	bad := map[string]string{
+		"ase":  "ASEF\x00\x01\x00\x00\x00\x00\x00\x01\x00\x01\x00\x00\x01\x00",
+		"aco":  "\x00\x01\x00\x02\x00\x00",
+		"gpl":  "GIMP Palette\n255 0\n",
+		"json": "{\"colors\":[{\"color\":{\"model\":\"rgb\"}}]}",
+		"":     "hello",
+	}
+	for format, data := range bad {
+		if _, err := importSwatches([]byte(data), format); err == nil {
+			t.Errorf("%q: expected an error", format)
+		}
+	}
+}
+
+func TestSwatchesExportLongName(t *testing.T) {
+	// длина строки в ASE и ACO - 16 бит: слишком длинное название - ошибка, а не испорченный файл
+	long := strings.Repeat("я", 1<<16)
+	for _, format := range []string{"ase", "aco"} {
+		for _, c := range []SwatchColor{
+			{Name: long, Color: ConvertRequest{CSS: "red"}},
+			{Name: "red", Group: long, Color: ConvertRequest{CSS: "red"}},
+		} {
+			if format == "aco" && c.Group != "" {
+				continue // в ACO групп нет
+			}
+			req := SwatchExportRequest{SwatchFile: SwatchFile{Colors: []SwatchColor{c}}, Format: format}
+			if _, _, err := exportSwatches(req); err == nil {
+				t.Errorf("%s: expected an error", format)
+			}
+		}
+	}
+
+	req := SwatchExportRequest{SwatchFile: SwatchFile{Colors: []SwatchColor{
+		{Name: strings.Repeat("я", 1<<16-2), Color: ConvertRequest{CSS: "red"}},
+	}}, Format: "ase"}
+	data, _, err := exportSwatches(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp, err := importSwatches(data, ""); err != nil || len([]rune(resp.Colors[0].Name)) != 1<<16-2 {
+		t.Errorf("longest name: %v", err)
+	}
+}
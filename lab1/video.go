@@ -0,0 +1,75 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/LordVillain/BSUComputerGraphicsLabs/lab1/colors"
+)
+
+// videoEncoding - стандарт матрицы и диапазон YCbCr из запроса
+func videoEncoding(req ConvertRequest) (standard colors.VideoStandard, limited bool, err error) {
+	switch req.Standard {
+	case "", "bt601":
+		standard = colors.BT601
+	case "bt709":
+		standard = colors.BT709
+	case "bt2020":
+		standard = colors.BT2020
+	default:
+		return 0, false, errors.New("standard must be one of: bt601, bt709, bt2020")
+	}
+	switch req.Range {
+	case "", "full":
+	case "limited":
+		limited = true
+	default:
+		return 0, false, errors.New("range must be one of: full, limited")
+	}
+	return standard, limited, nil
+}
+
+// resolveVideoColor - цвет из значений моделей ycbcr, yuv и yiq.
+// Значения за пределами диапазона кодирования отмечаются в inputClip, но не обрезаются:
+// в охват sRGB цвет приводится общей стратегией gamut.
+func resolveVideoColor(req ConvertRequest, inputClip map[string]float64) (colors.Color, error) {
+	standard, limited, err := videoEncoding(req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch req.Model {
+	case "ycbcr":
+		yf, okY := req.Values["y"]
+		cbf, okCb := req.Values["cb"]
+		crf, okCr := req.Values["cr"]
+		if !okY || !okCb || !okCr {
+			return nil, errors.New("ycbcr requires y,cb,cr")
+		}
+		addInputClip(inputClip, "y", yf, 0, 255)
+		addInputClip(inputClip, "cb", cbf, 0, 255)
+		addInputClip(inputClip, "cr", crf, 0, 255)
+		return colors.YCbCr{Y: yf, Cb: cbf, Cr: crf, Standard: standard, Limited: limited}, nil
+	case "yuv":
+		yf, okY := req.Values["y"]
+		uf, okU := req.Values["u"]
+		vf, okV := req.Values["v"]
+		if !okY || !okU || !okV {
+			return nil, errors.New("yuv requires y,u,v")
+		}
+		addInputClip(inputClip, "y", yf, 0, 1)
+		addInputClip(inputClip, "u", uf, -colors.UMax, colors.UMax)
+		addInputClip(inputClip, "v", vf, -colors.VMax, colors.VMax)
+		return colors.YUV{Y: yf, U: uf, V: vf, Standard: standard}, nil
+	}
+
+	yf, okY := req.Values["y"]
+	inf, okI := req.Values["i"]
+	qf, okQ := req.Values["q"]
+	if !okY || !okI || !okQ {
+		return nil, errors.New("yiq requires y,i,q")
+	}
+	addInputClip(inputClip, "y", yf, 0, 1)
+	addInputClip(inputClip, "i", inf, -colors.IMax, colors.IMax)
+	addInputClip(inputClip, "q", qf, -colors.QMax, colors.QMax)
+	return colors.YIQ{Y: yf, I: inf, Q: qf}, nil
+}
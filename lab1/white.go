@@ -0,0 +1,97 @@
+package main
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/LordVillain/BSUComputerGraphicsLabs/lab1/colors"
+)
+
+// AdaptedModel - цвет, пересчитанный хроматической адаптацией к другой белой точке
+type AdaptedModel struct {
+	White string     `json:"white"`
+	XYZ   colors.XYZ `json:"xyz"` // относительно белой точки White, Y 0..100
+	Lab   colors.Lab `json:"lab"` // относительно белой точки White
+}
+
+// Белые точки, которые можно указать в запросе
+var whitePoints = map[string]colors.XYZ{
+	"A":   colors.WhiteA,
+	"C":   colors.WhiteC,
+	"D50": colors.WhiteD50,
+	"D55": colors.WhiteD55,
+	"D65": colors.WhiteD65,
+	"D75": colors.WhiteD75,
+	"E":   colors.WhiteE,
+	"F2":  colors.WhiteF2,
+	"F7":  colors.WhiteF7,
+	"F11": colors.WhiteF11,
+}
+
+// parseWhite - белая точка по имени ("d50" и "D50" равнозначны)
+func parseWhite(name string) (colors.XYZ, error) {
+	white, ok := whitePoints[strings.ToUpper(name)]
+	if !ok {
+		return colors.XYZ{}, errors.New("white must be one of: A, C, D50, D55, D65, D75, E, F2, F7, F11")
+	}
+	return white, nil
+}
+
+func parseAdaptation(name string) (colors.Adaptation, error) {
+	switch name {
+	case "", "bradford":
+		return colors.Bradford, nil
+	case "vonkries":
+		return colors.VonKries, nil
+	case "xyz":
+		return colors.XYZScaling, nil
+	}
+	return 0, errors.New("adaptation must be one of: bradford, vonkries, xyz")
+}
+
+func parseCCTMethod(name string) (colors.CCTMethod, error) {
+	switch name {
+	case "", "robertson":
+		return colors.Robertson, nil
+	case "mccamy":
+		return colors.McCamy, nil
+	}
+	return 0, errors.New("cct_method must be one of: robertson, mccamy")
+}
+
+// adaptToD65 переводит XYZ, заданные относительно белой точки запроса (white), к D65
+func adaptToD65(xyz colors.XYZ, req ConvertRequest) (colors.XYZ, error) {
+	if req.White == "" {
+		return xyz, nil
+	}
+	white, err := parseWhite(req.White)
+	if err != nil {
+		return xyz, err
+	}
+	method, err := parseAdaptation(req.Adaptation)
+	if err != nil {
+		return xyz, err
+	}
+	return colors.Adapt(xyz, white, colors.WhiteD65, method), nil
+}
+
+// adaptedModel - цвет src относительно белой точки target_white запроса (nil, если она не задана)
+func adaptedModel(src colors.Color, req ConvertRequest) (*AdaptedModel, error) {
+	if req.TargetWhite == "" {
+		return nil, nil
+	}
+	white, err := parseWhite(req.TargetWhite)
+	if err != nil {
+		return nil, errors.New("target_" + err.Error())
+	}
+	method, err := parseAdaptation(req.Adaptation)
+	if err != nil {
+		return nil, err
+	}
+	xyz := colors.Adapt(colors.ToXYZ(src), colors.WhiteD65, white, method)
+	return &AdaptedModel{
+		White: strings.ToUpper(req.TargetWhite),
+		XYZ:   xyz.Rounded(),
+		Lab:   colors.LabFromXYZ(xyz, white).Rounded(),
+	}, nil
+}
@@ -0,0 +1,313 @@
+package main
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// ---------- WebSocket (RFC 6455), только сервер ----------
+//
+// Минимальная реализация без внешних зависимостей: рукопожатие через http.Hijacker,
+// текстовые и бинарные сообщения (с фрагментацией), ping/pong и закрытие.
+// Расширения (сжатие) и подпротоколы не поддерживаются.
+
+const (
+	wsOpContinuation = 0x0
+	wsOpText         = 0x1
+	wsOpBinary       = 0x2
+	wsOpClose        = 0x8
+	wsOpPing         = 0x9
+	wsOpPong         = 0xA
+)
+
+// Коды закрытия соединения
+const (
+	wsCloseNormal       = 1000
+	wsCloseProtocol     = 1002
+	wsCloseInvalidData  = 1007
+	wsCloseTooBig       = 1009
+	wsCloseInternalFail = 1011
+)
+
+// GUID из RFC 6455 для ответа Sec-WebSocket-Accept
+const wsGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// Ограничения: размер сообщения, время ожидания данных от клиента и записи
+const (
+	wsMaxMessage   = 1 << 20
+	wsReadTimeout  = 75 * time.Second
+	wsWriteTimeout = 10 * time.Second
+	wsPingEvery    = 30 * time.Second
+)
+
+var errWSClosed = errors.New("websocket: connection closed")
+
+// wsError - нарушение протокола клиентом: соединение закрывается с кодом code
+type wsError struct {
+	code   int
+	reason string
+}
+
+func (e *wsError) Error() string { return fmt.Sprintf("websocket: %s (%d)", e.reason, e.code) }
+
+type wsConn struct {
+	conn net.Conn
+	br   *bufio.Reader
+
+	wmu    sync.Mutex // запись кадров из разных горутин
+	closed bool
+}
+
+// wsAccept проверяет запрос на открытие WebSocket, отвечает 101 и забирает соединение у net/http.
+// При ошибке ответ клиенту уже отправлен.
+func wsAccept(w http.ResponseWriter, r *http.Request) (*wsConn, error) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "only GET allowed", http.StatusMethodNotAllowed)
+		return nil, errors.New("websocket: method " + r.Method)
+	}
+	if !headerHasToken(r.Header, "Connection", "upgrade") || !headerHasToken(r.Header, "Upgrade", "websocket") {
+		http.Error(w, "websocket upgrade required", http.StatusUpgradeRequired)
+		return nil, errors.New("websocket: not an upgrade request")
+	}
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		http.Error(w, "unsupported websocket version", http.StatusUpgradeRequired)
+		return nil, errors.New("websocket: unsupported version")
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if decoded, err := base64.StdEncoding.DecodeString(key); err != nil || len(decoded) != 16 {
+		http.Error(w, "invalid Sec-WebSocket-Key", http.StatusBadRequest)
+		return nil, errors.New("websocket: invalid key")
+	}
+	// браузер присылает Origin: чужие страницы не должны подключаться от имени пользователя
+	if origin := r.Header.Get("Origin"); origin != "" {
+		u, err := url.Parse(origin)
+		if err != nil || !strings.EqualFold(u.Host, r.Host) {
+			http.Error(w, "cross-origin websocket is not allowed", http.StatusForbidden)
+			return nil, errors.New("websocket: origin " + origin)
+		}
+	}
+
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "websocket is not supported by the server", http.StatusInternalServerError)
+		return nil, errors.New("websocket: response does not implement http.Hijacker")
+	}
+	conn, rw, err := hj.Hijack()
+	if err != nil {
+		return nil, err
+	}
+	sum := sha1.Sum([]byte(key + wsGUID))
+	rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n")
+	rw.WriteString("Sec-WebSocket-Accept: " + base64.StdEncoding.EncodeToString(sum[:]) + "\r\n\r\n")
+	if err := rw.Flush(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	conn.SetDeadline(time.Time{})
+	return &wsConn{conn: conn, br: rw.Reader}, nil
+}
+
+// headerHasToken - есть ли в заголовке (список через запятую) нужное значение без учета регистра
+func headerHasToken(h http.Header, name, token string) bool {
+	for _, v := range h.Values(name) {
+		for _, t := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ReadMessage возвращает следующее текстовое или бинарное сообщение, собирая фрагменты.
+// Ping получает ответ pong сам; на кадр close отвечает закрытием и возвращает io.EOF.
+func (c *wsConn) ReadMessage() (opcode byte, data []byte, err error) {
+	for {
+		c.conn.SetReadDeadline(time.Now().Add(wsReadTimeout))
+		fin, op, payload, err := c.readFrame()
+		if err != nil {
+			var perr *wsError
+			if errors.As(err, &perr) {
+				c.Close(perr.code, perr.reason)
+			}
+			return 0, nil, err
+		}
+
+		switch op {
+		case wsOpPing:
+			if err := c.writeFrame(wsOpPong, payload); err != nil {
+				return 0, nil, err
+			}
+			continue
+		case wsOpPong:
+			continue
+		case wsOpClose:
+			code := wsCloseNormal
+			if len(payload) >= 2 {
+				code = int(binary.BigEndian.Uint16(payload))
+			}
+			c.Close(code, "")
+			return 0, nil, io.EOF
+		case wsOpText, wsOpBinary:
+			if opcode != 0 {
+				return 0, nil, c.fail(wsCloseProtocol, "new message inside a fragmented one")
+			}
+			opcode = op
+		case wsOpContinuation:
+			if opcode == 0 {
+				return 0, nil, c.fail(wsCloseProtocol, "unexpected continuation frame")
+			}
+		default:
+			return 0, nil, c.fail(wsCloseProtocol, "unknown opcode")
+		}
+
+		if len(data)+len(payload) > wsMaxMessage {
+			return 0, nil, c.fail(wsCloseTooBig, "message too big")
+		}
+		data = append(data, payload...)
+		if fin {
+			if opcode == wsOpText && !utf8.Valid(data) {
+				return 0, nil, c.fail(wsCloseInvalidData, "invalid utf-8")
+			}
+			return opcode, data, nil
+		}
+	}
+}
+
+// wsCloseCode - код закрытия для ошибки ReadMessage: нарушение протокола - его код
+// (1002, 1007, 1009), закрытие клиентом - 1000, остальное (обрыв, таймаут) - 1011
+func wsCloseCode(err error) int {
+	var perr *wsError
+	switch {
+	case errors.As(err, &perr):
+		return perr.code
+	case errors.Is(err, io.EOF):
+		return wsCloseNormal
+	}
+	return wsCloseInternalFail
+}
+
+func (c *wsConn) fail(code int, reason string) error {
+	c.Close(code, reason)
+	return &wsError{code: code, reason: reason}
+}
+
+// readFrame читает один кадр; кадры клиента обязаны быть замаскированы
+func (c *wsConn) readFrame() (fin bool, opcode byte, payload []byte, err error) {
+	var head [2]byte
+	if _, err = io.ReadFull(c.br, head[:]); err != nil {
+		return
+	}
+	fin = head[0]&0x80 != 0
+	opcode = head[0] & 0x0F
+	if head[0]&0x70 != 0 {
+		return fin, opcode, nil, &wsError{wsCloseProtocol, "reserved bits set"}
+	}
+	if head[1]&0x80 == 0 {
+		return fin, opcode, nil, &wsError{wsCloseProtocol, "client frame is not masked"}
+	}
+
+	length := uint64(head[1] & 0x7F)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err = io.ReadFull(c.br, ext[:]); err != nil {
+			return
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err = io.ReadFull(c.br, ext[:]); err != nil {
+			return
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if opcode >= wsOpClose && (!fin || length > 125) {
+		return fin, opcode, nil, &wsError{wsCloseProtocol, "invalid control frame"}
+	}
+	if length > wsMaxMessage {
+		return fin, opcode, nil, &wsError{wsCloseTooBig, "message too big"}
+	}
+
+	var mask [4]byte
+	if _, err = io.ReadFull(c.br, mask[:]); err != nil {
+		return
+	}
+	payload = make([]byte, length)
+	if _, err = io.ReadFull(c.br, payload); err != nil {
+		return
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return fin, opcode, payload, nil
+}
+
+// WriteText отправляет текстовое сообщение одним кадром
+func (c *wsConn) WriteText(data []byte) error {
+	return c.writeFrame(wsOpText, data)
+}
+
+// Ping отправляет контрольный кадр, чтобы соединение не закрылось по таймауту
+func (c *wsConn) Ping() error {
+	return c.writeFrame(wsOpPing, nil)
+}
+
+// writeFrame пишет немаскированный кадр (сервер не маскирует данные)
+func (c *wsConn) writeFrame(opcode byte, payload []byte) error {
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+	if c.closed {
+		return errWSClosed
+	}
+
+	head := make([]byte, 2, 10)
+	head[0] = 0x80 | opcode
+	switch n := len(payload); {
+	case n <= 125:
+		head[1] = byte(n)
+	case n <= 0xFFFF:
+		head[1] = 126
+		head = binary.BigEndian.AppendUint16(head, uint16(n))
+	default:
+		head[1] = 127
+		head = binary.BigEndian.AppendUint64(head, uint64(n))
+	}
+
+	c.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+	if _, err := c.conn.Write(append(head, payload...)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Close отправляет кадр закрытия с кодом и причиной и закрывает соединение (повторные вызовы ничего не делают)
+func (c *wsConn) Close(code int, reason string) error {
+	payload := binary.BigEndian.AppendUint16(nil, uint16(code))
+	payload = append(payload, reason...)
+	if len(payload) > 125 {
+		payload = payload[:125]
+	}
+	c.writeFrame(wsOpClose, payload)
+
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	return c.conn.Close()
+}